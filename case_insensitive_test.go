@@ -0,0 +1,138 @@
+package searcher_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCaseInsensitiveConditions(t *testing.T) {
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "clients",
+		Fields: map[string]models.FieldMetaData{
+			"email": {Type: models.String, IsCaseInsensitive: true},
+			"code":  {Type: models.String},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	regex := func(pattern string) primitive.Regex { return primitive.Regex{Pattern: pattern, Options: "i"} }
+
+	tests := []struct {
+		name        string
+		condition   models.Condition
+		wantMongo   bson.M
+		wantElastic string
+	}{
+		{
+			name:        "equals escapes the metacharacters",
+			condition:   models.Condition{Field: "email", Operator: models.EqualsOperator, Value: "A.b+c@x.com"},
+			wantMongo:   bson.M{"email": bson.M{"$regex": regex(`^A\.b\+c@x\.com$`)}},
+			wantElastic: `{"bool":{"must":[{"term":{"email":{"case_insensitive":true,"value":"A.b+c@x.com"}}}]}}`,
+		},
+		{
+			name:        "not equals",
+			condition:   models.Condition{Field: "email", Operator: models.NotEqualsOperator, Value: "a(b)*"},
+			wantMongo:   bson.M{"email": bson.M{"$not": regex(`^a\(b\)\*$`)}},
+			wantElastic: `{"bool":{"must":[{"bool":{"must_not":[{"term":{"email":{"case_insensitive":true,"value":"a(b)*"}}}]}}]}}`,
+		},
+		{
+			name:        "in",
+			condition:   models.Condition{Field: "email", Operator: models.InOperator, Value: []string{"X", "y.z"}},
+			wantMongo:   bson.M{"email": bson.M{"$in": bson.A{regex(`^X$`), regex(`^y\.z$`)}}},
+			wantElastic: `{"bool":{"must":[{"bool":{"should":[{"term":{"email":{"case_insensitive":true,"value":"X"}}},{"term":{"email":{"case_insensitive":true,"value":"y.z"}}}]}}]}}`,
+		},
+		{
+			name:        "not in",
+			condition:   models.Condition{Field: "email", Operator: models.NotInOperator, Value: []string{"[x]"}},
+			wantMongo:   bson.M{"email": bson.M{"$nin": bson.A{regex(`^\[x\]$`)}}},
+			wantElastic: `{"bool":{"must":[{"bool":{"must_not":[{"bool":{"should":[{"term":{"email":{"case_insensitive":true,"value":"[x]"}}}]}}]}}]}}`,
+		},
+		{
+			name:        "case sensitive field",
+			condition:   models.Condition{Field: "code", Operator: models.EqualsOperator, Value: "A.b"},
+			wantMongo:   bson.M{"code": bson.M{"$eq": "A.b"}},
+			wantElastic: `{"bool":{"must":[{"term":{"code":"A.b"}}]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{tt.condition}}}}}
+
+			query, err := qt.ToMongo("clients", criteria, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := query["filters"], (bson.M{"$and": bson.A{tt.wantMongo}}); !reflect.DeepEqual(got, want) {
+				t.Errorf("filters = %#v, want %#v", got, want)
+			}
+
+			elasticQuery, err := qt.ToElastic("clients", criteria, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got struct {
+				Query json.RawMessage `json:"query"`
+			}
+			if err := json.Unmarshal([]byte(elasticQuery), &got); err != nil {
+				t.Fatal(err)
+			}
+			if string(got.Query) != tt.wantElastic {
+				t.Errorf("query = %s, want %s", got.Query, tt.wantElastic)
+			}
+		})
+	}
+}
+
+func TestCaseInsensitiveRegexMatchesLiterally(t *testing.T) {
+	// The escaped pattern matches the value itself ignoring the case and not the strings matched by its metacharacters
+	tests := []struct {
+		value string
+		match string
+		want  bool
+	}{
+		{value: "a.b", match: "A.B", want: true},
+		{value: "a.b", match: "aXb", want: false},
+		{value: "a+", match: "aa", want: false},
+		{value: "(x|y)", match: "(X|Y)", want: true},
+		{value: "(x|y)", match: "x", want: false},
+		{value: "abc", match: "xabcx", want: false},
+	}
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "clients",
+		Fields:     map[string]models.FieldMetaData{"email": {Type: models.String, IsCaseInsensitive: true}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.value+" "+tt.match, func(t *testing.T) {
+			criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+				Field: "email", Operator: models.EqualsOperator, Value: tt.value,
+			}}}}}}
+			query, err := qt.ToMongo("clients", criteria, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := query["filters"].(bson.M)["$and"].(bson.A)[0].(bson.M)["email"].(bson.M)
+			pattern := condition["$regex"].(primitive.Regex)
+			re := regexp.MustCompile("(?" + pattern.Options + ")" + pattern.Pattern)
+			if got := re.MatchString(tt.match); got != tt.want {
+				t.Errorf("%s matches %q = %v, want %v", pattern.Pattern, tt.match, got, tt.want)
+			}
+		})
+	}
+}
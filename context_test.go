@@ -0,0 +1,232 @@
+package searcher_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/domain/ports"
+	"github.com/solrac97gr/searcher/i18n"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+var _ ports.ContextQueryTranslator = &searcher.QueryTranslator{}
+
+// span is a translation recorded by recordingTracer
+type span struct {
+	backend string
+	entity  string
+	err     error
+	ended   bool
+}
+
+// recordingTracer is a ports.Tracer that records the translations
+type recordingTracer struct {
+	spans []*span
+}
+
+type spanKey struct{}
+
+func (r *recordingTracer) StartTranslation(ctx context.Context, backend string, entityName string) (context.Context, func(err error)) {
+	s := &span{backend: backend, entity: entityName}
+	r.spans = append(r.spans, s)
+	return context.WithValue(ctx, spanKey{}, s), func(err error) {
+		s.err = err
+		s.ended = true
+	}
+}
+
+func newClientsTranslator(t *testing.T, opts ...searcher.Option) *searcher.QueryTranslator {
+	t.Helper()
+	qt, err := searcher.NewQueryTranslator(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "clients",
+		Fields:     map[string]models.FieldMetaData{"name": {Type: models.String}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return qt
+}
+
+func TestTranslationsAreTraced(t *testing.T) {
+	valid := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+		Field: "name", Operator: models.EqualsOperator, Value: "John",
+	}}}}}}
+	invalid := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+		Field: "age", Operator: models.EqualsOperator, Value: 30,
+	}}}}}}
+
+	tests := []struct {
+		name        string
+		translate   func(qt *searcher.QueryTranslator, criteria models.Criteria) error
+		criteria    models.Criteria
+		wantBackend string
+		wantErr     bool
+	}{
+		{
+			name: "mongo",
+			translate: func(qt *searcher.QueryTranslator, criteria models.Criteria) error {
+				_, err := qt.ToMongoCtx(context.Background(), "clients", criteria, nil)
+				return err
+			},
+			criteria:    valid,
+			wantBackend: searcher.MongoDialect,
+		},
+		{
+			name: "elastic",
+			translate: func(qt *searcher.QueryTranslator, criteria models.Criteria) error {
+				_, err := qt.ToElasticCtx(context.Background(), "clients", criteria, nil)
+				return err
+			},
+			criteria:    valid,
+			wantBackend: searcher.ElasticDialect,
+		},
+		{
+			name: "mongo without context",
+			translate: func(qt *searcher.QueryTranslator, criteria models.Criteria) error {
+				_, err := qt.ToMongo("clients", criteria, nil)
+				return err
+			},
+			criteria:    valid,
+			wantBackend: searcher.MongoDialect,
+		},
+		{
+			name: "failed translation",
+			translate: func(qt *searcher.QueryTranslator, criteria models.Criteria) error {
+				_, err := qt.ToElasticCtx(context.Background(), "clients", criteria, nil)
+				return err
+			},
+			criteria:    invalid,
+			wantBackend: searcher.ElasticDialect,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := &recordingTracer{}
+			qt := newClientsTranslator(t, searcher.WithTracer(tracer))
+
+			err := tt.translate(qt, tt.criteria)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("translate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(tracer.spans) != 1 {
+				t.Fatalf("spans = %d, want 1", len(tracer.spans))
+			}
+			s := tracer.spans[0]
+			if s.backend != tt.wantBackend || s.entity != "clients" {
+				t.Errorf("span = %s %s, want %s clients", s.backend, s.entity, tt.wantBackend)
+			}
+			if !s.ended || s.err != err {
+				t.Errorf("span ended = %v with %v, want ended with %v", s.ended, s.err, err)
+			}
+		})
+	}
+}
+
+func TestTranslationsStopWhenTheContextIsDone(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), 0)
+	defer cancelExpired()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "canceled", ctx: canceled, wantErr: context.Canceled},
+		{name: "expired", ctx: expired, wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := &recordingTracer{}
+			qt := newClientsTranslator(t, searcher.WithTracer(tracer))
+
+			if _, err := qt.ToMongoCtx(tt.ctx, "clients", models.Criteria{}, nil); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ToMongoCtx() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := qt.ToElasticCtx(tt.ctx, "clients", models.Criteria{}, nil); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ToElasticCtx() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := qt.Hash("clients", models.Criteria{}); err != nil {
+				t.Errorf("Hash() error = %v, want the translator usable after a canceled translation", err)
+			}
+			for _, s := range tracer.spans {
+				if !errors.Is(s.err, tt.wantErr) {
+					t.Errorf("span error = %v, want %v", s.err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestTranslationErrorsAreLocalized(t *testing.T) {
+	catalog, err := i18n.NewCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+		Field: "age", Operator: models.EqualsOperator, Value: 30,
+	}}}}}}
+
+	tests := []struct {
+		name        string
+		opts        []searcher.Option
+		locale      string
+		wantMessage string
+	}{
+		{
+			name:        "spanish",
+			opts:        []searcher.Option{searcher.WithCatalog(catalog)},
+			locale:      "es",
+			wantMessage: "El campo age no existe o no puede ser usado",
+		},
+		{
+			name:        "portuguese",
+			opts:        []searcher.Option{searcher.WithCatalog(catalog)},
+			locale:      "pt-BR",
+			wantMessage: "O campo age não existe ou não pode ser usado",
+		},
+		{
+			name:        "without locale",
+			opts:        []searcher.Option{searcher.WithCatalog(catalog)},
+			wantMessage: "invalid field: age",
+		},
+		{
+			name:        "without catalog",
+			locale:      "es",
+			wantMessage: "invalid field: age",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qt := newClientsTranslator(t, tt.opts...)
+			ctx := context.Background()
+			if tt.locale != "" {
+				ctx = i18n.ContextWithLocale(ctx, tt.locale)
+			}
+
+			_, err := qt.ToMongoCtx(ctx, "clients", criteria, nil)
+			if !errors.Is(err, sentinels.ErrValidation) {
+				t.Fatalf("ToMongoCtx() error = %v, want a validation error", err)
+			}
+			fieldErrors := models.FieldErrors(err)
+			if len(fieldErrors) != 1 {
+				t.Fatalf("FieldErrors() = %v, want 1 error", fieldErrors)
+			}
+			fe := fieldErrors[0]
+			if fe.Code != models.UnknownFieldCode || fe.Path != "query.filters[0].conditions[0].field" {
+				t.Errorf("field error = %s %s, want unknown_field in query.filters[0].conditions[0].field", fe.Code, fe.Path)
+			}
+			if fe.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", fe.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package searcher
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+// jsonSchemaDialect is the JSON Schema version of the generated schemas, it's the dialect used by OpenAPI 3.1.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	// comparisonOperators are the operators of the fields that are not of type Geo
+	comparisonOperators = []models.Operator{
+		models.EqualsOperator,
+		models.NotEqualsOperator,
+		models.GreaterThan,
+		models.LessThan,
+		models.GreaterAndEqualsThan,
+		models.LessAndEqualsThan,
+	}
+	// listOperators are the operators of the fields that are not of type Geo with a list of values
+	listOperators = []models.Operator{
+		models.InOperator,
+		models.NotInOperator,
+	}
+	// geoOperators are the operators of the fields of type Geo
+	geoOperators = []models.Operator{
+		models.GeoDistanceOperator,
+		models.GeoBoundingBoxOperator,
+		models.GeoPolygonOperator,
+	}
+	// geoOperatorSchemas are the names of the schemas of the values of the geo operators
+	geoOperatorSchemas = map[models.Operator]string{
+		models.GeoDistanceOperator:    "GeoDistance",
+		models.GeoBoundingBoxOperator: "GeoBoundingBox",
+		models.GeoPolygonOperator:     "GeoPolygon",
+	}
+)
+
+// CriteriaJSONSchema returns the JSON Schema (draft 2020-12) of the Criteria body accepted by the translators for the
+// entity, the result can be encoded with encoding/json and served to the frontends. The schema contains:
+//
+// - the "field" of the conditions and the sorts as an enum of the filterable and sortable fields (aliases included).
+//
+// - the "operator" of each field restricted to the operators allowed for the field (check FieldMetaData.Operators),
+// without the range operators for the Boolean fields.
+//
+// - the "value" of each field restricted by its FieldType (e.g. a number for Number fields, a GeoDistance for the
+// geo_distance operator).
+//
+// - the pagination limits and the maximum number of filters, conditions and sorts of the entity (check
+// PaginationLimits and WithComplexityLimits).
+//
+// Only the fields visible for the roles are included (check models.FieldMetaData.Roles).
+func (ca *QueryTranslator) CriteriaJSONSchema(validMapEntityName string, roles ...string) (map[string]interface{}, error) {
+	schemas, err := ca.criteriaSchemas(validMapEntityName, roles, func(name string) string {
+		return "#/$defs/" + name
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"$schema": jsonSchemaDialect,
+		"title":   validMapEntityName + " criteria",
+		"$ref":    "#/$defs/Criteria",
+		"$defs":   schemas,
+	}, nil
+}
+
+// CriteriaOpenAPISchemas returns the same schemas of CriteriaJSONSchema for the components.schemas object of an
+// OpenAPI 3.1 document. The names of the schemas are prefixed with the entity name for avoid collisions between
+// entities (e.g. ClientsCriteria and ClientsCondition for the entity "clients"), use the <Prefix>Criteria schema
+// as the request body of the search endpoint. Only the fields visible for the roles are included.
+func (ca *QueryTranslator) CriteriaOpenAPISchemas(validMapEntityName string, roles ...string) (map[string]interface{}, error) {
+	prefix := schemaNamePrefix(validMapEntityName)
+	schemas, err := ca.criteriaSchemas(validMapEntityName, roles, func(name string) string {
+		return "#/components/schemas/" + prefix + name
+	})
+	if err != nil {
+		return nil, err
+	}
+	components := make(map[string]interface{}, len(schemas))
+	for name, schema := range schemas {
+		components[prefix+name] = schema
+	}
+	return components, nil
+}
+
+// criteriaSchemas builds the schemas of the Criteria of the entity by name with the fields visible for the roles,
+// ref returns the reference of a schema name.
+func (ca *QueryTranslator) criteriaSchemas(validMapEntityName string, roles []string, ref func(name string) string) (map[string]interface{}, error) {
+	vf, ok := ca.fieldSets.Lookup(validMapEntityName)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w: %s", sentinels.ErrValidation, ErrFieldSetNotFound, validMapEntityName)
+	}
+	vf = ca.withEntityLimits(vf).ForRoles(roles)
+	limits := vf.Limits
+	refTo := func(name string) map[string]interface{} {
+		return map[string]interface{}{"$ref": ref(name)}
+	}
+	logical := map[string]interface{}{
+		"type": "string",
+		"enum": []string{models.ANDLogical.String(), models.ORLogical.String()},
+	}
+
+	// The fields are walked sorted by name for generate always the same schema
+	names := make([]string, 0, len(vf.Fields))
+	for name := range vf.Fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	filterNames, sortNames := []string{}, []string{}
+	conditions := []interface{}{}
+	hasGeo := false
+	for _, name := range names {
+		fmd := vf.Fields[name]
+		// The aliases are accepted as the name of the field
+		fieldNames := append([]string{name}, fmd.Aliases...)
+		if !fmd.NotSortable {
+			sortNames = append(sortNames, fieldNames...)
+		}
+		if fmd.NotFilterable {
+			continue
+		}
+		filterNames = append(filterNames, fieldNames...)
+		hasGeo = hasGeo || fmd.Type.Equals(models.Geo)
+		for _, fieldName := range fieldNames {
+			conditions = append(conditions, conditionSchemas(fieldName, fmd, vf.Complexity.MaxListSize, refTo)...)
+		}
+	}
+
+	condition := map[string]interface{}{
+		"type":     "object",
+		"required": []string{"field", "operator", "value"},
+		"properties": map[string]interface{}{
+			"field":    map[string]interface{}{"type": "string", "enum": filterNames},
+			"operator": map[string]interface{}{"type": "string"},
+			"value":    map[string]interface{}{},
+		},
+	}
+	if len(conditions) > 0 {
+		condition["oneOf"] = conditions
+	}
+
+	schemas := map[string]interface{}{
+		"Criteria": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"pagination": refTo("Pagination"),
+				"query":      refTo("Query"),
+			},
+		},
+		"Pagination": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"minimum":     0,
+					"maximum":     limits.MaximumLimit,
+					"description": fmt.Sprintf("Number of items to return, %d when it's 0", limits.DefaultLimit),
+				},
+				"offset": map[string]interface{}{
+					"type":        "integer",
+					"minimum":     0,
+					"description": fmt.Sprintf("Number of items to skip, limit + offset must be less or equals %d", limits.MaximumLimitOffsetSize),
+				},
+			},
+		},
+		"Query": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"filters": arraySchema(refTo("Filter"), 0, vf.Complexity.MaxFilters),
+				"sorts":   arraySchema(refTo("Sort"), 0, vf.Complexity.MaxSorts),
+				"logical": logical,
+			},
+			// The logical operator is required for operate more than 1 filter (check Query.Validate)
+			"if":   map[string]interface{}{"required": []string{"filters"}, "properties": map[string]interface{}{"filters": map[string]interface{}{"minItems": 2}}},
+			"then": map[string]interface{}{"required": []string{"logical"}},
+		},
+		"Filter": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"conditions"},
+			"properties": map[string]interface{}{
+				"conditions": arraySchema(refTo("Condition"), 1, vf.Complexity.MaxConditionsPerFilter),
+				"logical":    logical,
+			},
+			// The logical operator is required for operate more than 1 condition (check Filter.Validate)
+			"if":   map[string]interface{}{"required": []string{"conditions"}, "properties": map[string]interface{}{"conditions": map[string]interface{}{"minItems": 2}}},
+			"then": map[string]interface{}{"required": []string{"logical"}},
+		},
+		"Condition": condition,
+		"Sort": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"field", "order"},
+			"properties": map[string]interface{}{
+				"field": map[string]interface{}{"type": "string", "enum": sortNames},
+				"order": map[string]interface{}{"type": "string", "enum": []string{models.ASCOrder.String(), models.DESCOrder.String()}},
+				"point": refTo("GeoPoint"),
+			},
+		},
+		"GeoPoint": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"lat", "lon"},
+			"properties": map[string]interface{}{
+				"lat": map[string]interface{}{"type": "number", "minimum": -90, "maximum": 90},
+				"lon": map[string]interface{}{"type": "number", "minimum": -180, "maximum": 180},
+			},
+		},
+	}
+	if hasGeo {
+		schemas["GeoDistance"] = map[string]interface{}{
+			"type":     "object",
+			"required": []string{"point", "distance"},
+			"properties": map[string]interface{}{
+				"point":    refTo("GeoPoint"),
+				"distance": map[string]interface{}{"type": "number", "exclusiveMinimum": 0},
+				"unit":     map[string]interface{}{"type": "string", "enum": []string{string(models.Meters), string(models.Kilometers), string(models.Miles)}},
+			},
+		}
+		schemas["GeoBoundingBox"] = map[string]interface{}{
+			"type":     "object",
+			"required": []string{"top_left", "bottom_right"},
+			"properties": map[string]interface{}{
+				"top_left":     refTo("GeoPoint"),
+				"bottom_right": refTo("GeoPoint"),
+			},
+		}
+		schemas["GeoPolygon"] = map[string]interface{}{
+			"type":     "object",
+			"required": []string{"points"},
+			"properties": map[string]interface{}{
+				"points": map[string]interface{}{"type": "array", "minItems": 3, "items": refTo("GeoPoint")},
+			},
+		}
+	}
+	return schemas, nil
+}
+
+// conditionSchemas returns the schemas of the conditions accepted for a field, the geo fields have one schema
+// per operator because the value depends on the operator as the list and exists operators.
+func conditionSchemas(fieldName string, fmd models.FieldMetaData, maxListSize int, refTo func(name string) map[string]interface{}) []interface{} {
+	conditionSchema := func(operators []models.Operator, value interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"properties": map[string]interface{}{
+				"field":    map[string]interface{}{"const": fieldName},
+				"operator": map[string]interface{}{"enum": operators},
+				"value":    value,
+			},
+		}
+	}
+
+	if fmd.Type.Equals(models.Geo) {
+		schemas := []interface{}{}
+		for _, operator := range geoOperators {
+			if fmd.AllowsOperator(operator) {
+				schemas = append(schemas, conditionSchema([]models.Operator{operator}, refTo(geoOperatorSchemas[operator])))
+			}
+		}
+		return schemas
+	}
+
+	schemas := []interface{}{}
+	operators := []models.Operator{}
+	for _, operator := range comparisonOperators {
+		// The range operators are rejected for the Boolean fields by the validation
+		if operator.IsRange() && fmd.Type.Equals(models.Boolean) {
+			continue
+		}
+		if fmd.AllowsOperator(operator) {
+			operators = append(operators, operator)
+		}
+	}
+	if len(operators) > 0 {
+		schemas = append(schemas, conditionSchema(operators, valueSchema(fmd.Type)))
+	}
+	operators = []models.Operator{}
+	for _, operator := range listOperators {
+		if fmd.AllowsOperator(operator) {
+			operators = append(operators, operator)
+		}
+	}
+	if len(operators) > 0 {
+		schemas = append(schemas, conditionSchema(operators, arraySchema(valueSchema(fmd.Type), 1, maxListSize)))
+	}
+	if fmd.AllowsOperator(models.ExistsOperator) {
+		schemas = append(schemas, conditionSchema([]models.Operator{models.ExistsOperator}, map[string]interface{}{"type": "boolean"}))
+	}
+	return schemas
+}
+
+// arraySchema returns the schema of an array of items, the minimum and maximum number of items are set when they are greater than 0.
+func arraySchema(items map[string]interface{}, minItems int, maxItems int) map[string]interface{} {
+	schema := map[string]interface{}{"type": "array", "items": items}
+	if minItems > 0 {
+		schema["minItems"] = minItems
+	}
+	if maxItems > 0 {
+		schema["maxItems"] = maxItems
+	}
+	return schema
+}
+
+// valueSchema returns the schema of the value of a condition for a FieldType.
+func valueSchema(fieldType models.FieldType) map[string]interface{} {
+	switch fieldType {
+	case models.String:
+		return map[string]interface{}{"type": "string"}
+	case models.Number:
+		return map[string]interface{}{"type": "number"}
+	case models.Boolean:
+		return map[string]interface{}{"type": "boolean"}
+	case models.ObjectID:
+		return map[string]interface{}{"type": "string", "pattern": "^[0-9a-fA-F]{24}$"}
+	case models.Date:
+		// The dates are parsed by the DateFormatter, the accepted formats depend on its configuration
+		return map[string]interface{}{
+			"type":        []string{"string", "number"},
+			"description": `Date in one of the configured layouts (RFC3339 by default), relative expression (e.g. "now-7d") or epoch timestamp`,
+		}
+	}
+	return map[string]interface{}{}
+}
+
+// schemaNamePrefix converts an entity name to a prefix for the OpenAPI schema names (e.g. "client_orders" to "ClientOrders").
+func schemaNamePrefix(entityName string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(entityName, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(part)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package searcher
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+func TestConditionSchemasOperators(t *testing.T) {
+	refTo := func(name string) map[string]interface{} { return map[string]interface{}{"$ref": name} }
+	tests := []struct {
+		name string
+		fmd  models.FieldMetaData
+		want [][]models.Operator
+	}{
+		{
+			name: "number",
+			fmd:  models.FieldMetaData{Type: models.Number},
+			want: [][]models.Operator{comparisonOperators, listOperators, {models.ExistsOperator}},
+		},
+		{
+			name: "boolean without range operators",
+			fmd:  models.FieldMetaData{Type: models.Boolean},
+			want: [][]models.Operator{{models.EqualsOperator, models.NotEqualsOperator}, listOperators, {models.ExistsOperator}},
+		},
+		{
+			name: "boolean with only range operators allowed",
+			fmd:  models.FieldMetaData{Type: models.Boolean, Operators: []models.Operator{models.GreaterThan, models.EqualsOperator}},
+			want: [][]models.Operator{{models.EqualsOperator}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]models.Operator
+			for _, schema := range conditionSchemas("field", tt.fmd, 0, refTo) {
+				properties := schema.(map[string]interface{})["properties"].(map[string]interface{})
+				got = append(got, properties["operator"].(map[string]interface{})["enum"].([]models.Operator))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("operators = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
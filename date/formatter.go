@@ -0,0 +1,93 @@
+package date
+
+import (
+	"time"
+
+	"github.com/solrac97gr/searcher/domain/ports"
+)
+
+// EpochUnit is the unit of the numeric timestamps accepted by the Formatter.
+type EpochUnit string
+
+const (
+	// EpochDisabled rejects the numeric timestamps.
+	EpochDisabled EpochUnit = ""
+	// EpochSeconds parses the numbers as seconds since the Unix epoch.
+	EpochSeconds EpochUnit = "seconds"
+	// EpochMillis parses the numbers as milliseconds since the Unix epoch.
+	EpochMillis EpochUnit = "millis"
+	// EpochAuto parses the numbers as milliseconds when they are too big for be
+	// seconds (greater than 1e11, that is the year 5138) and as seconds otherwise.
+	// The milliseconds before 1973-03-03 are read as seconds, use EpochMillis
+	// when the timestamps can be that old.
+	EpochAuto EpochUnit = "auto"
+)
+
+// Formatter implements ports.DateFormatter.
+type Formatter struct {
+	// now is the clock used for resolve the relative date expressions
+	now func() time.Time
+	// layouts are the accepted layouts for the absolute dates in order of preference
+	layouts []string
+	// location is the time zone applied to the values without time zone information
+	location *time.Location
+	// epoch is the unit of the numeric timestamps
+	epoch EpochUnit
+}
+
+// Option configures a Formatter.
+type Option func(*Formatter)
+
+// WithClock sets the clock used for resolve the relative date expressions
+// (e.g. "now-7d"), by default time.Now is used.
+func WithClock(now func() time.Time) Option {
+	return func(f *Formatter) {
+		f.now = now
+	}
+}
+
+// WithLayouts sets the ordered list of layouts (check the time package) accepted
+// for the absolute dates, the first layout that parse the value is used. The
+// layouts without time information (e.g. "2006-01-02") produce dates with Day
+// granularity. By default only time.RFC3339 is accepted.
+func WithLayouts(layouts ...string) Option {
+	return func(f *Formatter) {
+		f.layouts = layouts
+	}
+}
+
+// WithLocation sets the time zone used for the dates without time zone
+// information and for the relative date expressions, by default UTC.
+func WithLocation(location *time.Location) Option {
+	return func(f *Formatter) {
+		f.location = location
+	}
+}
+
+// WithEpoch enables the parsing of numeric timestamps in the given unit.
+func WithEpoch(unit EpochUnit) Option {
+	return func(f *Formatter) {
+		f.epoch = unit
+	}
+}
+
+// NewFormatter returns a new *date.Formatter implementing ports.DateFormatter.
+func NewFormatter(opts ...Option) (ports.DateFormatter, error) {
+	f := &Formatter{
+		now:      time.Now,
+		layouts:  []string{time.RFC3339},
+		location: time.UTC,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f, nil
+}
+
+// loc returns the location of the formatter, UTC if it's not set.
+func (f Formatter) loc() *time.Location {
+	if f.location == nil {
+		return time.UTC
+	}
+	return f.location
+}
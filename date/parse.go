@@ -0,0 +1,141 @@
+package date
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+// epochAutoThreshold is the limit for consider a timestamp in seconds when the EpochAuto unit is used.
+//
+// The timestamps in milliseconds below the threshold (before 1973-03-03) are read as seconds, so the
+// EpochMillis unit must be used when the dates can be that old.
+const epochAutoThreshold = 1e11
+
+// Parse takes the value of a date condition and returns the models.DateValue in UTC.
+//
+// The accepted values are:
+//
+// - strings with a relative date expression (check FromRelativeString).
+//
+// - strings in any of the layouts of the Formatter, the layouts without time
+// information produce a date with Day granularity.
+//
+// - numbers (or json.Number) with an epoch timestamp when the epoch parsing is enabled.
+//
+// - time.Time values.
+func (f Formatter) Parse(v interface{}) (models.DateValue, error) {
+	switch value := v.(type) {
+	case string:
+		return f.parseString(value)
+	case time.Time:
+		return instant(value), nil
+	case *time.Time:
+		if value != nil {
+			return instant(*value), nil
+		}
+	case json.Number:
+		n, err := value.Float64()
+		if err != nil {
+			return models.DateValue{}, fmt.Errorf("%w: invalid epoch timestamp: %s", sentinels.ErrValidation, value)
+		}
+		return f.fromEpoch(n)
+	case float64:
+		return f.fromEpoch(value)
+	case float32:
+		return f.fromEpoch(float64(value))
+	case int:
+		return f.fromEpoch(float64(value))
+	case int8:
+		return f.fromEpoch(float64(value))
+	case int16:
+		return f.fromEpoch(float64(value))
+	case int32:
+		return f.fromEpoch(float64(value))
+	case int64:
+		return f.fromEpoch(float64(value))
+	case uint:
+		return f.fromEpoch(float64(value))
+	case uint8:
+		return f.fromEpoch(float64(value))
+	case uint16:
+		return f.fromEpoch(float64(value))
+	case uint32:
+		return f.fromEpoch(float64(value))
+	case uint64:
+		return f.fromEpoch(float64(value))
+	}
+	return models.DateValue{}, fmt.Errorf("%w: invalid date: %v", sentinels.ErrValidation, v)
+}
+
+// parseString parses a relative date expression or an absolute date in any of the layouts.
+func (f Formatter) parseString(s string) (models.DateValue, error) {
+	if anchor, _ := splitAnchor(s); anchors[anchor] != nil {
+		t, err := f.FromRelativeString(s)
+		if err != nil {
+			return models.DateValue{}, err
+		}
+		return instant(*t), nil
+	}
+
+	for _, layout := range f.layouts {
+		t, err := time.ParseInLocation(layout, s, f.loc())
+		if err != nil {
+			continue
+		}
+		if !hasTime(layout) {
+			// The end of the day is calculated in the location for respect the days with daylight saving changes
+			return models.DateValue{Time: t.UTC(), End: t.AddDate(0, 0, 1).UTC(), Granularity: models.DayGranularity}, nil
+		}
+		return instant(t), nil
+	}
+
+	return models.DateValue{}, fmt.Errorf("%w: invalid date: %s accepted formats (%s)", sentinels.ErrValidation, s, strings.Join(f.layouts, ", "))
+}
+
+// fromEpoch converts an epoch timestamp in the unit of the formatter to a date.
+func (f Formatter) fromEpoch(n float64) (models.DateValue, error) {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return models.DateValue{}, fmt.Errorf("%w: invalid epoch timestamp: %v", sentinels.ErrValidation, n)
+	}
+
+	unit := f.epoch
+	if unit == EpochAuto {
+		unit = EpochSeconds
+		if math.Abs(n) >= epochAutoThreshold {
+			unit = EpochMillis
+		}
+	}
+
+	switch unit {
+	case EpochSeconds:
+		return instant(time.UnixMilli(int64(math.Round(n * 1000)))), nil
+	case EpochMillis:
+		return instant(time.UnixMilli(int64(math.Round(n)))), nil
+	}
+	return models.DateValue{}, fmt.Errorf("%w: invalid date: epoch timestamps are not accepted: %v", sentinels.ErrValidation, n)
+}
+
+// instant creates a date with Instant granularity in UTC.
+func instant(t time.Time) models.DateValue {
+	t = t.UTC()
+	return models.DateValue{Time: t, End: t, Granularity: models.InstantGranularity}
+}
+
+// timeProbe is a date with all the time components set for detect the layouts with time information.
+var timeProbe = time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC)
+
+// hasTime checks if the layout contains time information formatting a date with time and parsing it back,
+// the time is lost when the layout only has date components.
+func hasTime(layout string) bool {
+	t, err := time.Parse(layout, timeProbe.Format(layout))
+	if err != nil {
+		return true
+	}
+	return t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0
+}
@@ -0,0 +1,76 @@
+package date
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestHasTime(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   bool
+	}{
+		{layout: time.DateOnly, want: false},
+		{layout: "02/01/2006", want: false},
+		{layout: "Jan _2 2006", want: false},
+		{layout: time.RFC3339, want: true},
+		{layout: time.DateTime, want: true},
+		{layout: "2006-01-02 3PM", want: true},
+		{layout: "2006-01-02 03:04", want: true},
+		{layout: time.Kitchen, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			if got := hasTime(tt.layout); got != tt.want {
+				t.Errorf("hasTime(%q) = %v, want %v", tt.layout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEpochIntegers(t *testing.T) {
+	f := Formatter{epoch: EpochSeconds}
+	want := time.Unix(100, 0).UTC()
+	tests := []interface{}{
+		int(100), int8(100), int16(100), int32(100), int64(100),
+		uint(100), uint8(100), uint16(100), uint32(100), uint64(100),
+		float32(100), float64(100),
+	}
+	for _, value := range tests {
+		t.Run(fmt.Sprintf("%T", value), func(t *testing.T) {
+			got, err := f.Parse(value)
+			if err != nil {
+				t.Fatalf("Parse(%T) error = %v", value, err)
+			}
+			if !got.Time.Equal(want) {
+				t.Errorf("Parse(%T) = %v, want %v", value, got.Time, want)
+			}
+		})
+	}
+}
+
+func TestParseEpochAuto(t *testing.T) {
+	f := Formatter{epoch: EpochAuto}
+	tests := []struct {
+		name  string
+		value int64
+		want  time.Time
+	}{
+		{name: "seconds", value: 1710498600, want: time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)},
+		{name: "millis", value: 1710498600000, want: time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)},
+		// The milliseconds before 1973-03-03 are below the threshold and are read as seconds
+		{name: "millis before 1973", value: 86400000, want: time.Unix(86400000, 0).UTC()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.Parse(tt.value)
+			if err != nil {
+				t.Fatalf("Parse(%d) error = %v", tt.value, err)
+			}
+			if !got.Time.Equal(tt.want) {
+				t.Errorf("Parse(%d) = %v, want %v", tt.value, got.Time, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package date
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+// anchors are the named starting points of a relative date expression.
+var anchors = map[string]func(time.Time) time.Time{
+	"now":          func(t time.Time) time.Time { return t },
+	"today":        func(t time.Time) time.Time { return roundDown(t, 'd') },
+	"startOfDay":   func(t time.Time) time.Time { return roundDown(t, 'd') },
+	"startOfWeek":  func(t time.Time) time.Time { return roundDown(t, 'w') },
+	"startOfMonth": func(t time.Time) time.Time { return roundDown(t, 'M') },
+	"startOfYear":  func(t time.Time) time.Time { return roundDown(t, 'y') },
+}
+
+// FromRelativeString takes a relative date expression and returns the
+// resolved *time.Time in UTC using the clock and the location of the Formatter.
+//
+// The expression starts with an anchor (now, today, startOfDay, startOfWeek,
+// startOfMonth or startOfYear) followed by any number of operations using the
+// Elasticsearch date math syntax:
+//
+// - "+1d" or "-7d": add or subtract an amount of units.
+//
+// - "/d": round down to the start of the unit.
+//
+// The units are y (years), M (months), w (weeks), d (days), h or H (hours),
+// m (minutes) and s (seconds). e.g. "now-7d", "now/d", "startOfMonth-1M".
+func (f Formatter) FromRelativeString(s string) (*time.Time, error) {
+	anchor, operations := splitAnchor(s)
+	resolve, ok := anchors[anchor]
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid relative date: %s", sentinels.ErrValidation, s)
+	}
+
+	now := time.Now
+	if f.now != nil {
+		now = f.now
+	}
+	// The expression is resolved in the location of the formatter so "now/d" is the start of the local day
+	t := resolve(now().In(f.loc()))
+
+	for operations != "" {
+		op := operations[0]
+		operations = operations[1:]
+		switch op {
+		case '/':
+			if operations == "" || !isUnit(operations[0]) {
+				return nil, fmt.Errorf("%w: invalid relative date: %s missing rounding unit", sentinels.ErrValidation, s)
+			}
+			t = roundDown(t, operations[0])
+			operations = operations[1:]
+		case '+', '-':
+			digits := 0
+			for digits < len(operations) && operations[digits] >= '0' && operations[digits] <= '9' {
+				digits++
+			}
+			amount := 1
+			if digits > 0 {
+				var err error
+				if amount, err = strconv.Atoi(operations[:digits]); err != nil {
+					return nil, fmt.Errorf("%w: invalid relative date: %s amount out of range", sentinels.ErrValidation, s)
+				}
+			}
+			if digits == len(operations) || !isUnit(operations[digits]) {
+				return nil, fmt.Errorf("%w: invalid relative date: %s missing unit", sentinels.ErrValidation, s)
+			}
+			if op == '-' {
+				amount = -amount
+			}
+			var ok bool
+			if t, ok = add(t, amount, operations[digits]); !ok {
+				return nil, fmt.Errorf("%w: invalid relative date: %s amount out of range", sentinels.ErrValidation, s)
+			}
+			operations = operations[digits+1:]
+		default:
+			return nil, fmt.Errorf("%w: invalid relative date: %s unexpected %q", sentinels.ErrValidation, s, op)
+		}
+	}
+
+	t = t.UTC()
+	return &t, nil
+}
+
+// splitAnchor splits the expression in the anchor name and the date math operations.
+func splitAnchor(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	index := strings.IndexAny(s, "+-/")
+	if index < 0 {
+		return s, ""
+	}
+	return s[:index], s[index:]
+}
+
+func isUnit(u byte) bool {
+	return strings.IndexByte("yMwdhHms", u) >= 0
+}
+
+// maxRelativeYear is the last year that a relative date can reach, the last year of the RFC 3339 dates.
+const maxRelativeYear = 9999
+
+// add adds an amount of units to the time, ok is false when the result is out of the years 0 to 9999.
+func add(t time.Time, amount int, unit byte) (result time.Time, ok bool) {
+	var duration time.Duration
+	switch unit {
+	case 'h', 'H':
+		duration = time.Hour
+	case 'm':
+		duration = time.Minute
+	case 's':
+		duration = time.Second
+	default:
+		// Every calendar unit is at least a day, so bigger amounts are out of range and could overflow AddDate
+		if amount > 366*maxRelativeYear || amount < -366*maxRelativeYear {
+			return t, false
+		}
+	}
+	switch unit {
+	case 'y':
+		result = t.AddDate(amount, 0, 0)
+	case 'M':
+		result = t.AddDate(0, amount, 0)
+	case 'w':
+		result = t.AddDate(0, 0, 7*amount)
+	case 'd':
+		result = t.AddDate(0, 0, amount)
+	default:
+		// The amounts of time units are limited by the range of time.Duration, about 292 years
+		if int64(amount) > math.MaxInt64/int64(duration) || int64(amount) < math.MinInt64/int64(duration) {
+			return t, false
+		}
+		result = t.Add(time.Duration(amount) * duration)
+	}
+	return result, result.Year() >= 0 && result.Year() <= maxRelativeYear
+}
+
+// roundDown rounds the time down to the start of the unit, the weeks start on Monday.
+func roundDown(t time.Time, unit byte) time.Time {
+	year, month, day := t.Date()
+	switch unit {
+	case 'y':
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location())
+	case 'M':
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case 'w':
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, t.Location())
+	case 'd':
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	case 'h', 'H':
+		return t.Truncate(time.Hour)
+	case 'm':
+		return t.Truncate(time.Minute)
+	}
+	return t.Truncate(time.Second)
+}
@@ -0,0 +1,121 @@
+package date
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+func TestFromRelativeStringOutOfRange(t *testing.T) {
+	f := Formatter{now: func() time.Time { return time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC) }}
+	tests := []string{
+		"now-99999999999999999999d",
+		"now+99999999999999999999s",
+		"now+9999999999y",
+		"now-3000000d",
+		"now+8000y",
+		"now+3000000h",
+		"now-9999999999s",
+	}
+	for _, expression := range tests {
+		t.Run(expression, func(t *testing.T) {
+			got, err := f.FromRelativeString(expression)
+			if !errors.Is(err, sentinels.ErrValidation) {
+				t.Fatalf("FromRelativeString(%q) = %v, %v, want a validation error", expression, got, err)
+			}
+		})
+	}
+}
+
+func TestFromRelativeString(t *testing.T) {
+	// 2024-03-15 is a Friday
+	now := time.Date(2024, time.March, 15, 10, 30, 45, 0, time.UTC)
+	f := Formatter{now: func() time.Time { return now }}
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{expression: "now", want: now},
+		{expression: " now ", want: now},
+		{expression: "now-7d", want: time.Date(2024, time.March, 8, 10, 30, 45, 0, time.UTC)},
+		{expression: "now+d", want: time.Date(2024, time.March, 16, 10, 30, 45, 0, time.UTC)},
+		{expression: "now+2w", want: time.Date(2024, time.March, 29, 10, 30, 45, 0, time.UTC)},
+		{expression: "now-1y", want: time.Date(2023, time.March, 15, 10, 30, 45, 0, time.UTC)},
+		{expression: "now-30m", want: time.Date(2024, time.March, 15, 10, 0, 45, 0, time.UTC)},
+		{expression: "now+15s", want: time.Date(2024, time.March, 15, 10, 31, 0, 0, time.UTC)},
+		{expression: "now-2H", want: time.Date(2024, time.March, 15, 8, 30, 45, 0, time.UTC)},
+		{expression: "now/d", want: time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{expression: "now+1h/h", want: time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{expression: "now-1M/M", want: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "now/w", want: time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)},
+		{expression: "now/m", want: time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)},
+		{expression: "today", want: time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{expression: "startOfDay+1d", want: time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{expression: "startOfWeek", want: time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)},
+		{expression: "startOfMonth-1M", want: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "startOfYear+1y", want: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := f.FromRelativeString(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("FromRelativeString(%q) = %v, want %v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromRelativeStringLocation(t *testing.T) {
+	// 2024-03-15T01:00:00Z is still 2024-03-14 in UTC-3
+	now := time.Date(2024, time.March, 15, 1, 0, 0, 0, time.UTC)
+	f := Formatter{now: func() time.Time { return now }, location: time.FixedZone("UTC-3", -3*60*60)}
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{expression: "today", want: time.Date(2024, time.March, 14, 3, 0, 0, 0, time.UTC)},
+		{expression: "now/d+1d", want: time.Date(2024, time.March, 15, 3, 0, 0, 0, time.UTC)},
+		{expression: "startOfMonth", want: time.Date(2024, time.March, 1, 3, 0, 0, 0, time.UTC)},
+		{expression: "now-1h", want: time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := f.FromRelativeString(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("FromRelativeString(%q) = %v, want %v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromRelativeStringInvalid(t *testing.T) {
+	f := Formatter{}
+	tests := []string{
+		"",
+		"yesterday",
+		"2024-03-15",
+		"now-7",
+		"now-7x",
+		"now/",
+		"now/x",
+		"now*2d",
+		"now-d7",
+		"today+1d-",
+	}
+	for _, expression := range tests {
+		t.Run(expression, func(t *testing.T) {
+			got, err := f.FromRelativeString(expression)
+			if !errors.Is(err, sentinels.ErrValidation) {
+				t.Fatalf("FromRelativeString(%q) = %v, %v, want a validation error", expression, got, err)
+			}
+		})
+	}
+}
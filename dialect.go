@@ -0,0 +1,165 @@
+package searcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/domain/ports"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+// Names of the built-in dialects.
+const (
+	// MongoDialect translates the criteria to a models.MongoQuery (check ToMongo)
+	MongoDialect = "mongo"
+	// ElasticDialect translates the criteria to an Elasticsearch query string (check ToElastic)
+	ElasticDialect = "elastic"
+)
+
+var (
+	// ErrDialectNotFound is returned when there is no dialect registered with a name
+	ErrDialectNotFound = errors.New("dialect not found")
+	// ErrDialectAlreadyExists is returned when a dialect is registered with the name of another dialect
+	ErrDialectAlreadyExists = errors.New("the dialect already exists")
+)
+
+var _ ports.Translator = &QueryTranslator{}
+
+// dialectRegistry is a concurrency-safe registry of the dialects by name.
+type dialectRegistry struct {
+	mu       sync.RWMutex
+	dialects map[string]ports.Dialect
+}
+
+// newDialectRegistry creates an empty dialectRegistry.
+func newDialectRegistry() *dialectRegistry {
+	return &dialectRegistry{dialects: make(map[string]ports.Dialect)}
+}
+
+// register adds a dialect, it fails if the name is empty or already registered.
+func (r *dialectRegistry) register(dialect ports.Dialect) error {
+	if dialect == nil || dialect.Name() == "" {
+		return errors.New("the dialect must have a name")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.dialects[dialect.Name()]; ok {
+		return fmt.Errorf("%w: %s", ErrDialectAlreadyExists, dialect.Name())
+	}
+	r.dialects[dialect.Name()] = dialect
+	return nil
+}
+
+// lookup returns the dialect with the name.
+func (r *dialectRegistry) lookup(name string) (ports.Dialect, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	dialect, ok := r.dialects[name]
+	return dialect, ok
+}
+
+// names returns the sorted names of the dialects.
+func (r *dialectRegistry) names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.dialects))
+	for name := range r.dialects {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RegisterDialect adds a dialect for translate the criteria to a new backend with Translate, the names of the
+// built-in dialects (MongoDialect and ElasticDialect) cannot be used.
+func (ca *QueryTranslator) RegisterDialect(dialect ports.Dialect) error {
+	return ca.dialects.register(dialect)
+}
+
+// Dialects returns the sorted names of the registered dialects.
+func (ca *QueryTranslator) Dialects() []string {
+	return ca.dialects.names()
+}
+
+// Translate converts the criteria of the entity with the dialect registered with the name (e.g. MongoDialect).
+// Before call the dialect the criteria is prepared with the defaults and validated against the fields visible
+// for the principal of the context, and the filters of the policies are added to the super filters, so every
+// dialect gets the same guarantees. The translation is traced (check WithTracer) and the validation errors are
+// localized to the locale of the context (check WithCatalog).
+func (ca *QueryTranslator) Translate(ctx context.Context, dialect string, validMapEntityName string, criteria models.Criteria, superFilters ...models.SuperFilter) (interface{}, error) {
+	d, ok := ca.dialects.lookup(dialect)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDialectNotFound, dialect)
+	}
+
+	ctx, finish := ca.startTranslation(ctx, dialect, validMapEntityName)
+	query, err := ca.translate(ctx, d, validMapEntityName, criteria, superFilters)
+	err = ca.localizeError(ctx, err)
+	finish(err)
+	return query, err
+}
+
+// translate builds the TranslationRequest of the criteria and calls the dialect.
+func (ca *QueryTranslator) translate(ctx context.Context, dialect ports.Dialect, validMapEntityName string, criteria models.Criteria, superFilters []models.SuperFilter) (interface{}, error) {
+	request, err := ca.translationRequest(ctx, validMapEntityName, criteria, superFilters)
+	if err != nil {
+		return nil, err
+	}
+	return dialect.Translate(ctx, request)
+}
+
+// translationRequest validates and normalizes the criteria and the super filters for the dialects.
+func (ca *QueryTranslator) translationRequest(ctx context.Context, validMapEntityName string, rawCriteria models.Criteria, superFilters []models.SuperFilter) (ports.TranslationRequest, error) {
+	// The translation is not started when the request is already canceled
+	if err := ctx.Err(); err != nil {
+		return ports.TranslationRequest{}, err
+	}
+
+	// Check if the valid fields are correctly registered
+	vf, ok := ca.fieldSets.Lookup(validMapEntityName)
+	if !ok {
+		return ports.TranslationRequest{}, fmt.Errorf("%w: %w: %s", sentinels.ErrValidation, ErrFieldSetNotFound, validMapEntityName)
+	}
+	vf = ca.withEntityLimits(vf)
+	// The super filters are not restricted by the roles so their fields keep their metadata
+	superFilterFields := vf.Fields
+
+	// The fields that the roles of the principal cannot see are removed, they are rejected as unknown fields
+	vf = vf.ForRoles(rolesFromContext(ctx))
+
+	// The mandatory filters of the policies are applied as super filters
+	superFilters, err := ca.applyPolicies(ctx, validMapEntityName, superFilters)
+	if err != nil {
+		return ports.TranslationRequest{}, err
+	}
+
+	// We need to pre-process the criteria adding default values in case of some conditions are matched (check PrepareCriteria())
+	criteria := ca.prepareCriteria(&rawCriteria, vf.Limits)
+
+	// Check the whole criteria against the valid fields and the limits of the entity reporting all the problems at once
+	if err := ca.validateCriteria(vf, *criteria); err != nil {
+		return ports.TranslationRequest{}, err
+	}
+	if err := validateSuperFilters(superFilters); err != nil {
+		return ports.TranslationRequest{}, err
+	}
+
+	// The conditions are resolved, converted and simplified once for all the dialects
+	tree, err := ca.queryTree(vf, superFilterFields, *criteria, superFilters)
+	if err != nil {
+		return ports.TranslationRequest{}, err
+	}
+
+	return ports.TranslationRequest{
+		EntityName:    validMapEntityName,
+		Fields:        vf,
+		Criteria:      *criteria,
+		SuperFilters:  superFilters,
+		Tree:          tree,
+		DateFormatter: ca.dateFormatter,
+	}, nil
+}
@@ -0,0 +1,242 @@
+package searcher_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/domain/ports"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+// recordingDialect is a ports.Dialect that returns the requests it receives
+type recordingDialect struct {
+	name     string
+	err      error
+	requests []ports.TranslationRequest
+}
+
+func (d *recordingDialect) Name() string {
+	return d.name
+}
+
+func (d *recordingDialect) Translate(_ context.Context, request ports.TranslationRequest) (interface{}, error) {
+	d.requests = append(d.requests, request)
+	return request, d.err
+}
+
+func TestDialectRegistry(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []searcher.Option
+		register   []ports.Dialect
+		wantNames  []string
+		wantErr    error
+		wantNewErr bool
+	}{
+		{name: "built-in dialects", wantNames: []string{searcher.ElasticDialect, searcher.MongoDialect}},
+		{
+			name:      "option",
+			opts:      []searcher.Option{searcher.WithDialect(&recordingDialect{name: "sql"})},
+			wantNames: []string{searcher.ElasticDialect, searcher.MongoDialect, "sql"},
+		},
+		{
+			name:      "register",
+			register:  []ports.Dialect{&recordingDialect{name: "sql"}},
+			wantNames: []string{searcher.ElasticDialect, searcher.MongoDialect, "sql"},
+		},
+		{
+			name:      "duplicated registration",
+			register:  []ports.Dialect{&recordingDialect{name: "sql"}, &recordingDialect{name: "sql"}},
+			wantNames: []string{searcher.ElasticDialect, searcher.MongoDialect, "sql"},
+			wantErr:   searcher.ErrDialectAlreadyExists,
+		},
+		{
+			name:      "option and registration with the same name",
+			opts:      []searcher.Option{searcher.WithDialect(&recordingDialect{name: "sql"})},
+			register:  []ports.Dialect{&recordingDialect{name: "sql"}},
+			wantNames: []string{searcher.ElasticDialect, searcher.MongoDialect, "sql"},
+			wantErr:   searcher.ErrDialectAlreadyExists,
+		},
+		{
+			name:      "built-in dialects cannot be replaced",
+			register:  []ports.Dialect{&recordingDialect{name: searcher.MongoDialect}},
+			wantNames: []string{searcher.ElasticDialect, searcher.MongoDialect},
+			wantErr:   searcher.ErrDialectAlreadyExists,
+		},
+		{
+			name:       "option with a built-in name",
+			opts:       []searcher.Option{searcher.WithDialect(&recordingDialect{name: searcher.ElasticDialect})},
+			wantNewErr: true,
+		},
+		{
+			name:       "option without name",
+			opts:       []searcher.Option{searcher.WithDialect(&recordingDialect{})},
+			wantNewErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qt, err := searcher.NewQueryTranslator(tt.opts...)
+			if tt.wantNewErr {
+				if err == nil {
+					t.Fatal("NewQueryTranslator() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var registerErr error
+			for _, dialect := range tt.register {
+				if err := qt.RegisterDialect(dialect); err != nil {
+					registerErr = err
+				}
+			}
+			if !errors.Is(registerErr, tt.wantErr) || (tt.wantErr == nil && registerErr != nil) {
+				t.Errorf("RegisterDialect() error = %v, want %v", registerErr, tt.wantErr)
+			}
+			if got := qt.Dialects(); !slices.Equal(got, tt.wantNames) {
+				t.Errorf("Dialects() = %v, want %v", got, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestRegisterDialectWithoutName(t *testing.T) {
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dialect := range []ports.Dialect{nil, &recordingDialect{}} {
+		if err := qt.RegisterDialect(dialect); err == nil {
+			t.Errorf("RegisterDialect(%v) error = nil, want an error", dialect)
+		}
+	}
+}
+
+func TestRegisterDialectConcurrently(t *testing.T) {
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := qt.RegisterDialect(&recordingDialect{name: fmt.Sprintf("dialect-%d", i)}); err != nil {
+				t.Error(err)
+			}
+			qt.Dialects()
+		}(i)
+	}
+	wg.Wait()
+	if got := len(qt.Dialects()); got != 22 {
+		t.Errorf("len(Dialects()) = %d, want 22", got)
+	}
+}
+
+func TestTranslateWithDialects(t *testing.T) {
+	sql := &recordingDialect{name: "sql"}
+	qt, err := searcher.NewQueryTranslator(searcher.WithDialect(sql))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "clients",
+		Fields: map[string]models.FieldMetaData{
+			"name":   {Type: models.String},
+			"salary": {Type: models.Number, Roles: []string{"admin"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+		Field: "name", Operator: models.EqualsOperator, Value: "John",
+	}}}}}}
+	superFilter := models.SuperFilter{Field: "tenant_id", Value: "t1"}
+
+	t.Run("built-in dialects", func(t *testing.T) {
+		mongoQuery, err := qt.Translate(context.Background(), searcher.MongoDialect, "clients", criteria, superFilter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantMongo, err := qt.ToMongo("clients", criteria, []models.SuperFilter{superFilter})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(mongoQuery, wantMongo) {
+			t.Errorf("Translate(mongo) = %v, want %v", mongoQuery, wantMongo)
+		}
+
+		elasticQuery, err := qt.Translate(context.Background(), searcher.ElasticDialect, "clients", criteria, superFilter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantElastic, err := qt.ToElastic("clients", criteria, []models.SuperFilter{superFilter})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if elasticQuery != wantElastic {
+			t.Errorf("Translate(elastic) = %v, want %v", elasticQuery, wantElastic)
+		}
+	})
+
+	t.Run("custom dialect", func(t *testing.T) {
+		if _, err := qt.Translate(context.Background(), "sql", "clients", criteria, superFilter); err != nil {
+			t.Fatal(err)
+		}
+		if len(sql.requests) != 1 {
+			t.Fatalf("requests = %d, want 1", len(sql.requests))
+		}
+		request := sql.requests[0]
+		if request.EntityName != "clients" || request.Criteria.Pagination.Limit != models.DefaultPaginationLimit {
+			t.Errorf("request = %s with limit %d, want the prepared criteria of clients", request.EntityName, request.Criteria.Pagination.Limit)
+		}
+		if _, ok := request.Fields.Fields["salary"]; ok {
+			t.Error("request.Fields has salary, want the fields visible without roles")
+		}
+		if !reflect.DeepEqual(request.SuperFilters, []models.SuperFilter{superFilter}) {
+			t.Errorf("request.SuperFilters = %v, want %v", request.SuperFilters, []models.SuperFilter{superFilter})
+		}
+		if request.Tree.Root == nil || request.DateFormatter == nil {
+			t.Error("request without Tree or DateFormatter")
+		}
+	})
+
+	t.Run("invalid criteria is not sent to the dialect", func(t *testing.T) {
+		sql.requests = nil
+		invalid := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+			Field: "salary", Operator: models.GreaterThan, Value: 1000,
+		}}}}}}
+		if _, err := qt.Translate(context.Background(), "sql", "clients", invalid); !errors.Is(err, sentinels.ErrValidation) {
+			t.Errorf("Translate() error = %v, want a validation error", err)
+		}
+		if len(sql.requests) != 0 {
+			t.Errorf("requests = %d, want 0", len(sql.requests))
+		}
+	})
+
+	t.Run("unknown dialect", func(t *testing.T) {
+		if _, err := qt.Translate(context.Background(), "cassandra", "clients", criteria); !errors.Is(err, searcher.ErrDialectNotFound) {
+			t.Errorf("Translate() error = %v, want %v", err, searcher.ErrDialectNotFound)
+		}
+	})
+
+	t.Run("dialect error", func(t *testing.T) {
+		failing := &recordingDialect{name: "failing", err: errors.New("unsupported operator")}
+		if err := qt.RegisterDialect(failing); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := qt.Translate(context.Background(), "failing", "clients", criteria); !errors.Is(err, failing.err) {
+			t.Errorf("Translate() error = %v, want %v", err, failing.err)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ComplexityLimits are the limits applied to the size of a criteria for avoid expensive queries.
+// They can be configured per translator (searcher.WithComplexityLimits) and per entity
+// (ValidFields.Complexity), the limits that are not set (0) are not applied.
+type ComplexityLimits struct {
+	// MaxFilters is the maximum number of filters of the query
+	MaxFilters int
+	// MaxConditionsPerFilter is the maximum number of conditions of each filter
+	MaxConditionsPerFilter int
+	// MaxConditions is the maximum number of conditions of all the filters
+	MaxConditions int
+	// MaxDepth is the maximum number of nested logical groups (check Query.Depth)
+	MaxDepth int
+	// MaxListSize is the maximum number of elements of the list values of the conditions
+	MaxListSize int
+	// MaxSorts is the maximum number of sorts of the query
+	MaxSorts int
+	// MaxCost is the maximum cost of the criteria (check Criteria.Cost)
+	MaxCost int
+}
+
+// WithFallback returns the limits replacing the values that are not set (0) with the values of the fallback limits.
+func (l ComplexityLimits) WithFallback(fallback ComplexityLimits) ComplexityLimits {
+	return ComplexityLimits{
+		MaxFilters:             orFallback(l.MaxFilters, fallback.MaxFilters),
+		MaxConditionsPerFilter: orFallback(l.MaxConditionsPerFilter, fallback.MaxConditionsPerFilter),
+		MaxConditions:          orFallback(l.MaxConditions, fallback.MaxConditions),
+		MaxDepth:               orFallback(l.MaxDepth, fallback.MaxDepth),
+		MaxListSize:            orFallback(l.MaxListSize, fallback.MaxListSize),
+		MaxSorts:               orFallback(l.MaxSorts, fallback.MaxSorts),
+		MaxCost:                orFallback(l.MaxCost, fallback.MaxCost),
+	}
+}
+
+// orFallback returns the fallback when the value is not set (0).
+func orFallback(value int, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+// CostWeights are the weights used for compute the cost of a criteria (check Criteria.Cost).
+type CostWeights struct {
+	// Equality is the cost of the "=" conditions
+	Equality int
+	// NotEquals is the cost of the "!=" and "not_in" conditions, the negations cannot use the indexes efficiently
+	NotEquals int
+	// Range is the cost of the ">", "<", ">=" and "<=" conditions
+	Range int
+	// Geo is the cost of the geo conditions
+	Geo int
+	// CaseInsensitive is the cost added to the conditions of case insensitive fields (a regex in MongoDB)
+	CaseInsensitive int
+	// ListItem is the cost added for each element of the list values
+	ListItem int
+	// Sort is the cost of each sort
+	Sort int
+	// NotIndexedFactor multiplies the cost of the conditions and sorts of the fields marked as NotIndexed
+	NotIndexedFactor int
+}
+
+// DefaultCostWeights are the weights used when no other weights are configured.
+var DefaultCostWeights = CostWeights{
+	Equality:         1,
+	NotEquals:        2,
+	Range:            2,
+	Geo:              5,
+	CaseInsensitive:  3,
+	ListItem:         1,
+	Sort:             1,
+	NotIndexedFactor: 10,
+}
+
+// operatorCost returns the cost of an operator, the unknown operators cost the same as the equality.
+func (w CostWeights) operatorCost(o Operator) int {
+	switch {
+	case o.Equals(NotEqualsOperator), o.Equals(NotInOperator):
+		return w.NotEquals
+	case o.Equals(GreaterThan), o.Equals(LessThan), o.Equals(GreaterAndEqualsThan), o.Equals(LessAndEqualsThan):
+		return w.Range
+	case o.IsGeo():
+		return w.Geo
+	}
+	return w.Equality
+}
+
+// fieldFactor returns the multiplier of the cost of a field.
+func (w CostWeights) fieldFactor(fmd FieldMetaData) int {
+	if fmd.NotIndexed && w.NotIndexedFactor > 0 {
+		return w.NotIndexedFactor
+	}
+	return 1
+}
+
+// Depth returns the depth of the tree of logical groups of the query: the filters are grouped by the logical
+// operator of the query and the conditions by the logical operator of their filter, so it's 2 for any query with
+// filters (0 without filters). The filters cannot nest groups yet, a MaxDepth of 2 or more accepts all the queries
+// today and keeps limiting them when the nested groups are supported.
+func (q Query) Depth() int {
+	if len(q.Filters) == 0 {
+		return 0
+	}
+	return 2
+}
+
+// Cost returns an estimation of the cost of executing the criteria: the sum of the weights of the operators
+// of the conditions and of the sorts, multiplied when the fields are NotIndexed. The unknown fields are
+// considered indexed, they are rejected by the validation anyway.
+func (c Criteria) Cost(vf ValidFields, weights CostWeights) int {
+	cost := 0
+	for _, filter := range c.Query.Filters {
+		for _, condition := range filter.Conditions {
+			fmd, _ := vf.FilterField(condition.Field.String())
+			conditionCost := weights.operatorCost(condition.Operator)
+			if fmd.IsCaseInsensitive {
+				conditionCost += weights.CaseInsensitive
+			}
+			if size, ok := listSize(condition.Value); ok {
+				conditionCost += size * weights.ListItem
+			}
+			cost += conditionCost * weights.fieldFactor(fmd)
+		}
+	}
+	for _, sort := range c.Query.Sorts {
+		fmd, _ := vf.SortField(sort.Field)
+		cost += weights.Sort * weights.fieldFactor(fmd)
+	}
+	return cost
+}
+
+// ValidateComplexity checks the size and the cost of the criteria against the limits, all the exceeded limits are
+// reported as FieldErrors with the code LimitExceededCode.
+func (c Criteria) ValidateComplexity(limits ComplexityLimits, vf ValidFields, weights CostWeights) error {
+	var validationErrors ValidationErrors
+	exceeded := func(path string, value int, limit int, message string) {
+		validationErrors = append(validationErrors, NewFieldError(path, LimitExceededCode, value, fmt.Sprintf("%s must be less or equals %d", message, limit)).
+			WithParam("limit", limit))
+	}
+
+	if limits.MaxFilters > 0 && len(c.Query.Filters) > limits.MaxFilters {
+		exceeded("query.filters", len(c.Query.Filters), limits.MaxFilters, "the number of filters")
+	}
+	if depth := c.Query.Depth(); limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		exceeded("query", depth, limits.MaxDepth, "the depth of the query")
+	}
+
+	totalConditions := 0
+	for filterIndex, filter := range c.Query.Filters {
+		totalConditions += len(filter.Conditions)
+		if limits.MaxConditionsPerFilter > 0 && len(filter.Conditions) > limits.MaxConditionsPerFilter {
+			exceeded(fmt.Sprintf("query.filters[%d].conditions", filterIndex), len(filter.Conditions), limits.MaxConditionsPerFilter, "the number of conditions of the filter")
+		}
+		for conditionIndex, condition := range filter.Conditions {
+			if size, ok := listSize(condition.Value); ok && limits.MaxListSize > 0 && size > limits.MaxListSize {
+				exceeded(fmt.Sprintf("query.filters[%d].conditions[%d].value", filterIndex, conditionIndex), size, limits.MaxListSize, "the number of values")
+			}
+		}
+	}
+	if limits.MaxConditions > 0 && totalConditions > limits.MaxConditions {
+		exceeded("query.filters", totalConditions, limits.MaxConditions, "the number of conditions")
+	}
+
+	if limits.MaxSorts > 0 && len(c.Query.Sorts) > limits.MaxSorts {
+		exceeded("query.sorts", len(c.Query.Sorts), limits.MaxSorts, "the number of sorts")
+	}
+	if cost := c.Cost(vf, weights); limits.MaxCost > 0 && cost > limits.MaxCost {
+		exceeded("query", cost, limits.MaxCost, "the cost of the query")
+	}
+	return validationErrors.orNil()
+}
+
+// listSize returns the number of elements of a list value, ok is false if the value is not a list.
+func listSize(v interface{}) (size int, ok bool) {
+	if v == nil {
+		return 0, false
+	}
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return 0, false
+	}
+	return value.Len(), true
+}
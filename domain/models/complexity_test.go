@@ -0,0 +1,45 @@
+package models
+
+import "testing"
+
+func TestValidateComplexityDepth(t *testing.T) {
+	filter := Filter{Logical: ANDLogical, Conditions: Conditions{{Field: "amount", Operator: EqualsOperator, Value: 1}}}
+	tests := []struct {
+		name      string
+		query     Query
+		maxDepth  int
+		wantDepth int
+		wantError bool
+	}{
+		{name: "without filters", query: Query{}, maxDepth: 1, wantDepth: 0},
+		{name: "with filters", query: Query{Filters: Filters{filter}}, maxDepth: 2, wantDepth: 2},
+		{name: "unlimited", query: Query{Filters: Filters{filter, filter}}, maxDepth: 0, wantDepth: 2},
+		{name: "exceeded", query: Query{Filters: Filters{filter}}, maxDepth: 1, wantDepth: 2, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Depth(); got != tt.wantDepth {
+				t.Errorf("Depth() = %d, want %d", got, tt.wantDepth)
+			}
+			err := Criteria{Query: tt.query}.ValidateComplexity(ComplexityLimits{MaxDepth: tt.maxDepth}, ValidFields{}, DefaultCostWeights)
+			fieldErrors := FieldErrors(err)
+			if !tt.wantError {
+				if err != nil {
+					t.Fatalf("ValidateComplexity() error = %v", err)
+				}
+				return
+			}
+			if len(fieldErrors) != 1 || fieldErrors[0].Path != "query" || fieldErrors[0].Code != LimitExceededCode {
+				t.Fatalf("ValidateComplexity() error = %v, want a limit_exceeded error for query", err)
+			}
+		})
+	}
+}
+
+func TestComplexityLimitsWithFallback(t *testing.T) {
+	limits := ComplexityLimits{MaxDepth: 3, MaxFilters: 5}.WithFallback(ComplexityLimits{MaxDepth: 2, MaxSorts: 1})
+	want := ComplexityLimits{MaxDepth: 3, MaxFilters: 5, MaxSorts: 1}
+	if limits != want {
+		t.Errorf("WithFallback() = %+v, want %+v", limits, want)
+	}
+}
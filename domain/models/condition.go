@@ -1,9 +1,10 @@
 package models
 
 import (
-	"errors"
 	"fmt"
 	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Conditions represents a collection of conditions.
@@ -12,21 +13,20 @@ type Conditions []Condition
 // Validate checks the validity of each condition in the collection.
 // It returns a ValidationErrors slice if any conditions fail validation.
 func (cs Conditions) Validate() error {
+	return cs.validate(nil).orNil()
+}
+
+// validate checks every condition of the collection, when vf is not nil the conditions are checked against the valid fields too.
+func (cs Conditions) validate(vf *ValidFields) ValidationErrors {
 	if len(cs) == 0 {
-		return errors.New("empty Conditions: at least one condition must be specified")
+		return ValidationErrors{NewFieldError("", RequiredCode, nil, "empty Conditions: at least one condition must be specified")}
 	}
 
 	var validationErrors ValidationErrors
 	for index, condition := range cs {
-		if err := condition.Validate(); err != nil {
-			newErr := fmt.Errorf("condition[%v]: %v", index, err)
-			validationErrors = append(validationErrors, newErr)
-		}
-	}
-	if len(validationErrors) > 0 {
-		return validationErrors
+		validationErrors = validationErrors.appendPrefixed(fmt.Sprintf("[%v]", index), condition.validate(vf).orNil())
 	}
-	return nil
+	return validationErrors
 }
 
 // Len returns the number of elements in the collection
@@ -46,28 +46,126 @@ type emptyStruct struct{}
 // Validate checks the validity of the condition.
 // It returns a ValidationErrors slice if any validation rules fail.
 func (c Condition) Validate() error {
+	return c.validate(nil).orNil()
+}
+
+// validate checks the condition, when vf is not nil the condition is checked against the valid fields too.
+func (c Condition) validate(vf *ValidFields) ValidationErrors {
 	var validationErrors ValidationErrors
 	if err := c.Field.Validate(); err != nil {
-		validationErrors = append(validationErrors, err)
+		validationErrors = append(validationErrors, newFieldError("field", RequiredCode, nil, err))
+	}
+	if c.Operator.String() == "" {
+		validationErrors = append(validationErrors, NewFieldError("operator", RequiredCode, nil, "invalid operator: empty operator"))
+	} else if err := c.Operator.Validate(); err != nil {
+		validationErrors = append(validationErrors, newFieldError("operator", InvalidOperatorCode, c.Operator, err))
 	}
 
 	if c.Value == nil {
-		validationErrors = append(validationErrors, errors.New("invalid value: cannot be nil"))
+		validationErrors = append(validationErrors, NewFieldError("value", RequiredCode, nil, "invalid value: cannot be nil"))
 	} else {
 		valueType := reflect.TypeOf(c.Value)
 		if valueType.Kind() == reflect.Struct && valueType == reflect.TypeOf(emptyStruct{}) {
-			validationErrors = append(validationErrors, errors.New("invalid value: cannot be an empty struct"))
+			validationErrors = append(validationErrors, NewFieldError("value", InvalidValueCode, nil, "invalid value: cannot be an empty struct"))
 		}
 
+		// Any map type is accepted (e.g. bson.M or map[string]string), only the empty maps are rejected
 		if valueType.Kind() == reflect.Map {
-			if len(c.Value.(map[string]interface{})) == 0 {
-				validationErrors = append(validationErrors, errors.New("invalid value: cannot be empty map"))
+			if reflect.ValueOf(c.Value).Len() == 0 {
+				validationErrors = append(validationErrors, NewFieldError("value", InvalidValueCode, c.Value, "invalid value: cannot be empty map"))
 			}
 		}
 	}
 
-	if len(validationErrors) > 0 {
-		return validationErrors
+	// The geo operators need a structured value (check the geo.go file)
+	if c.Operator.IsGeo() && c.Value != nil {
+		if err := ValidateGeoValue(c.Operator, c.Value); err != nil {
+			validationErrors = append(validationErrors, newFieldError("value", InvalidValueCode, c.Value, err))
+		}
+	}
+
+	// The list operators need a non empty list and the exists operator a boolean
+	if c.Operator.IsList() && c.Value != nil {
+		if size, ok := listSize(c.Value); !ok || size == 0 {
+			validationErrors = append(validationErrors, NewFieldError("value", InvalidValueCode, c.Value, fmt.Sprintf("invalid value: the operator %s needs a non empty list of values", c.Operator)))
+		}
+	}
+	if c.Operator.Equals(ExistsOperator) && c.Value != nil {
+		if _, ok := c.Value.(bool); !ok {
+			validationErrors = append(validationErrors, NewFieldError("value", InvalidValueCode, c.Value, "invalid value: the operator exists needs a boolean"))
+		}
+	}
+
+	if vf != nil && c.Field.String() != "" {
+		validationErrors = append(validationErrors, c.validateField(*vf)...)
+	}
+	return validationErrors
+}
+
+// validateField checks that the field of the condition can be filtered and that the operator and the value are valid
+// for the field. The date values are not checked because their formats depend on the DateFormatter of the translator.
+func (c Condition) validateField(vf ValidFields) ValidationErrors {
+	fieldMetaData, ok := vf.FilterField(c.Field.String())
+	if !ok {
+		return ValidationErrors{NewFieldError("field", UnknownFieldCode, c.Field, "invalid field: "+c.Field.String())}
+	}
+
+	var validationErrors ValidationErrors
+	// The geo operators only can be applied to geo fields and the geo fields only support geo operators
+	validOperator := fieldMetaData.AllowsOperator(c.Operator) && fieldMetaData.Type.Equals(Geo) == c.Operator.IsGeo()
+	if c.Operator.Validate() == nil && !validOperator {
+		validationErrors = append(validationErrors, NewFieldError("operator", InvalidOperatorCode, c.Operator, fmt.Sprintf("invalid operator %s for field: %s", c.Operator, c.Field)))
+	}
+	if fieldMetaData.Type.Equals(ObjectID) {
+		if values, ok := ListValues(c.Value); ok && c.Operator.IsList() {
+			for index, v := range values {
+				if value, ok := v.(string); ok && !primitive.IsValidObjectID(value) {
+					validationErrors = append(validationErrors, NewFieldError(fmt.Sprintf("value[%d]", index), TypeMismatchCode, v, "invalid object id value for field: "+c.Field.String()))
+				}
+			}
+		} else if value, ok := c.Value.(string); ok && !primitive.IsValidObjectID(value) {
+			validationErrors = append(validationErrors, NewFieldError("value", TypeMismatchCode, c.Value, "invalid object id value for field: "+c.Field.String()))
+		}
+	}
+	if c.Operator.IsRange() && validOperator && c.Value != nil {
+		validationErrors = append(validationErrors, c.validateRange(fieldMetaData)...)
+	}
+	return validationErrors
+}
+
+// validateRange checks that the value of a range condition can be compared with the values of the field: the
+// numbers are compared numerically and the strings lexicographically, so a "5" string is not accepted for a
+// Number field (it would be compared as a string by the backends) and the Boolean fields have no ranges.
+func (c Condition) validateRange(fmd FieldMetaData) ValidationErrors {
+	switch fmd.Type {
+	case Number:
+		if _, ok := toFloat(c.Value); !ok {
+			return ValidationErrors{NewFieldError("value", TypeMismatchCode, c.Value, fmt.Sprintf("invalid value: the operator %s needs a number for field: %s", c.Operator, c.Field))}
+		}
+	case String, ObjectID:
+		if _, ok := c.Value.(string); !ok {
+			return ValidationErrors{NewFieldError("value", TypeMismatchCode, c.Value, fmt.Sprintf("invalid value: the operator %s needs a string for field: %s", c.Operator, c.Field))}
+		}
+	case Boolean:
+		return ValidationErrors{NewFieldError("operator", InvalidOperatorCode, c.Operator, fmt.Sprintf("invalid operator %s for field: %s", c.Operator, c.Field))}
 	}
 	return nil
 }
+
+// ListValues returns the elements of a list value (e.g. the value of the "in" operator), ok is false if the
+// value is not a list.
+func ListValues(v interface{}) (values []interface{}, ok bool) {
+	if values, ok := v.([]interface{}); ok {
+		return values, true
+	}
+	size, ok := listSize(v)
+	if !ok {
+		return nil, false
+	}
+	value := reflect.ValueOf(v)
+	values = make([]interface{}, 0, size)
+	for i := 0; i < size; i++ {
+		values = append(values, value.Index(i).Interface())
+	}
+	return values, true
+}
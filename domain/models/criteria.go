@@ -18,11 +18,33 @@ type Criteria struct {
 // Validate checks the validity of the Criteria.
 // It returns a standard validation error (/pkg/errors) if any validation rules fail.
 func (c Criteria) Validate() error {
-	if err := c.Pagination.Validate(); err != nil {
-		return fmt.Errorf("%w: %v", sentinels.ErrValidation, err)
-	}
-	if err := c.Query.Validate(); err != nil {
-		return fmt.Errorf("%w: %v", sentinels.ErrValidation, err)
+	return c.ValidateWithLimits(DefaultPaginationLimits)
+}
+
+// ValidateWithLimits checks the validity of the Criteria using the given pagination limits
+// (e.g. the limits of an entity returned by QueryTranslator.PaginationLimits).
+// It returns a standard validation error (/pkg/errors) if any validation rules fail, the
+// FieldErrors with the path of every invalid part can be obtained with FieldErrors(err).
+func (c Criteria) ValidateWithLimits(limits PaginationLimits) error {
+	return c.validate(limits, nil)
+}
+
+// ValidateAgainst checks the validity of the Criteria and checks the conditions and sorts against the valid fields
+// of an entity (unknown fields, operators not allowed for the field, invalid ObjectIds...) using the pagination
+// limits of the entity. Unlike the translators it doesn't stop at the first problem, all of them are reported
+// as FieldErrors (check FieldErrors). The date values are checked by QueryTranslator.ValidateCriteria.
+func (c Criteria) ValidateAgainst(vf ValidFields) error {
+	return c.validate(vf.Limits.WithFallback(DefaultPaginationLimits), &vf)
+}
+
+// validate checks the Criteria collecting all the errors, when vf is not nil the conditions and sorts are checked
+// against the valid fields too.
+func (c Criteria) validate(limits PaginationLimits, vf *ValidFields) error {
+	var validationErrors ValidationErrors
+	validationErrors = validationErrors.appendPrefixed("pagination", c.Pagination.ValidateLimits(limits))
+	validationErrors = validationErrors.appendPrefixed("query", c.Query.validate(vf).orNil())
+	if len(validationErrors) > 0 {
+		return fmt.Errorf("%w: %w", sentinels.ErrValidation, validationErrors)
 	}
 	return nil
 }
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Granularity is the precision of a parsed date.
+type Granularity string
+
+const (
+	// InstantGranularity is a date with time information (e.g. 2024-01-31T10:00:00Z).
+	InstantGranularity Granularity = "instant"
+	// DayGranularity is a date without time information (e.g. 2024-01-31) that represents the whole day.
+	DayGranularity Granularity = "day"
+)
+
+// DateValue is a parsed date with its granularity.
+type DateValue struct {
+	// Time is the parsed date in UTC, for the Day granularity it's the start of the day
+	Time time.Time
+	// End is the exclusive end of the date in UTC, for the Day granularity it's the
+	// start of the next day and for the Instant granularity it's equal to Time
+	End         time.Time
+	Granularity Granularity
+}
+
+// IsDay checks if the date represents a whole day.
+func (d DateValue) IsDay() bool {
+	return d.Granularity == DayGranularity
+}
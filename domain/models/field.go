@@ -1,6 +1,10 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
 
 // Field represents a field name.
 type Field string
@@ -23,8 +27,38 @@ const (
 	String    FieldType = "string"
 	Number    FieldType = "number"
 	Date      FieldType = "date"
+	Geo       FieldType = "geo"
+	Boolean   FieldType = "boolean"
+	// ObjectID is a MongoDB ObjectId, the hexadecimal string values are converted to ObjectId in the Mongo queries
+	ObjectID FieldType = "object_id"
 )
 
+var validFieldTypes = map[FieldType]bool{
+	String:   true,
+	Number:   true,
+	Date:     true,
+	Geo:      true,
+	Boolean:  true,
+	ObjectID: true,
+}
+
+// NewFieldType creates a new FieldType based on the given string.
+func NewFieldType(s string) (FieldType, error) {
+	ft := FieldType(s)
+	if err := ft.Validate(); err != nil {
+		return ft, err
+	}
+	return ft, nil
+}
+
+// Validate checks that the FieldType is one of the predefined field types (except Undefined).
+func (ft FieldType) Validate() error {
+	if !validFieldTypes[ft] {
+		return fmt.Errorf("invalid field type [available:(string,number,date,geo,boolean,object_id)]: %s", ft)
+	}
+	return nil
+}
+
 func (ft FieldType) String() string {
 	return string(ft)
 }
@@ -38,6 +72,53 @@ type FieldMetaData struct {
 	Field      Field
 	Type       FieldType
 	IsAnalyzed bool
+	// IsCaseInsensitive makes the equality operators ("=" and "!=") ignore the
+	// case of string values, the caller don't need to lowercase the values.
+	IsCaseInsensitive bool
+	// MultiValued marks the fields that hold arrays, the conditions match the documents with any element that
+	// satisfies them so the optimizer doesn't merge their ranges or look for contradictions (check Optimize)
+	MultiValued bool
+	// NotFilterable excludes the field from the fields that can be used in the conditions
+	NotFilterable bool
+	// NotSortable excludes the field from the fields that can be used in the sorts
+	NotSortable bool
+	// NotIndexed marks the fields without index in the database, their conditions and sorts are more
+	// expensive (check Criteria.Cost)
+	NotIndexed bool
+	// Aliases are alternative names accepted for the field in the conditions and sorts,
+	// the translated queries always use the name of the field
+	Aliases []string
+	// Operators are the operators allowed for the field, if it's empty all the operators are allowed
+	Operators []Operator
+	// Roles are the roles allowed to filter and sort by the field (any of them), if it's empty the field is visible
+	// for everyone. For the callers without the roles the field doesn't exist (check ValidFields.ForRoles)
+	Roles []string
+}
+
+// AllowsOperator checks if the operator can be used in the conditions of the field.
+func (fmd FieldMetaData) AllowsOperator(o Operator) bool {
+	if len(fmd.Operators) == 0 {
+		return true
+	}
+	for _, allowed := range fmd.Operators {
+		if allowed.Equals(o) {
+			return true
+		}
+	}
+	return false
+}
+
+// VisibleTo checks if the caller with the roles can filter and sort by the field.
+func (fmd FieldMetaData) VisibleTo(roles []string) bool {
+	if len(fmd.Roles) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		if slices.Contains(fmd.Roles, role) {
+			return true
+		}
+	}
+	return false
 }
 
 // Validate checks the validity of the Field.
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+// ErrorCode is a machine-readable identifier of the reason of a FieldError.
+type ErrorCode string
+
+// Predefined error codes.
+const (
+	// RequiredCode the value is missing or empty
+	RequiredCode ErrorCode = "required"
+	// InvalidValueCode the value is not valid (e.g. an unknown logical operator or a polygon with 2 points)
+	InvalidValueCode ErrorCode = "invalid_value"
+	// UnknownFieldCode the field is not a valid field of the entity (or it cannot be used for filter or sort)
+	UnknownFieldCode ErrorCode = "unknown_field"
+	// InvalidOperatorCode the operator cannot be used with the field
+	InvalidOperatorCode ErrorCode = "invalid_operator"
+	// TypeMismatchCode the value doesn't match the type of the field (e.g. an invalid date for a Date field)
+	TypeMismatchCode ErrorCode = "type_mismatch"
+	// LimitExceededCode the value is greater than the allowed limit (e.g. the pagination limit)
+	LimitExceededCode ErrorCode = "limit_exceeded"
+	// UnindexedFilterCode the conditions cannot use an index of the entity (a warning of Criteria.LintIndexes)
+	UnindexedFilterCode ErrorCode = "unindexed_filter"
+	// UnindexedSortCode the sorts cannot use an index of the entity (a warning of Criteria.LintIndexes)
+	UnindexedSortCode ErrorCode = "unindexed_sort"
+)
+
+// FieldError is a validation error of a part of the Criteria, it can be returned by the API as is.
+// It matches sentinels.ErrValidation with errors.Is and it's found by errors.As inside of the
+// ValidationErrors and the errors returned by the translators.
+type FieldError struct {
+	// Path is the location of the invalid part in the Criteria body (e.g. query.filters[1].conditions[0].value)
+	Path string `json:"path"`
+	// Code is the reason of the error
+	Code ErrorCode `json:"code"`
+	// Value is the offending value
+	Value interface{} `json:"value,omitempty"`
+	// Message is a human-readable description of the error
+	Message string `json:"message"`
+	// Params are the values used by the localized messages in addition to the Value (e.g. the "limit" of limit_exceeded)
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// NewFieldError creates a FieldError.
+func NewFieldError(path string, code ErrorCode, value interface{}, message string) *FieldError {
+	return &FieldError{Path: path, Code: code, Value: value, Message: message}
+}
+
+// newFieldError creates a FieldError with the message of err.
+func newFieldError(path string, code ErrorCode, value interface{}, err error) *FieldError {
+	return NewFieldError(path, code, value, err.Error())
+}
+
+// WithParam sets a param of the localized messages of the error (check Params) and returns the error.
+func (fe *FieldError) WithParam(key string, value interface{}) *FieldError {
+	if fe.Params == nil {
+		fe.Params = make(map[string]interface{})
+	}
+	fe.Params[key] = value
+	return fe
+}
+
+// Error returns the path and the message of the error.
+func (fe *FieldError) Error() string {
+	if fe.Path == "" {
+		return fe.Message
+	}
+	return fe.Path + ": " + fe.Message
+}
+
+// Is reports that every FieldError is a validation error.
+func (fe *FieldError) Is(target error) bool {
+	return target == sentinels.ErrValidation
+}
+
+// WithPathPrefix returns err with the prefix added to the path of its FieldErrors (e.g. the prefix "query"
+// and the path "filters[0].logical" are joined as "query.filters[0].logical"), the ValidationErrors are
+// flattened and the other errors are converted to FieldErrors with the code InvalidValueCode.
+func WithPathPrefix(prefix string, err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case ValidationErrors:
+		prefixed := make(ValidationErrors, 0, len(e))
+		for _, nested := range e {
+			if p, ok := WithPathPrefix(prefix, nested).(ValidationErrors); ok {
+				prefixed = append(prefixed, p...)
+				continue
+			}
+			prefixed = append(prefixed, WithPathPrefix(prefix, nested))
+		}
+		return prefixed
+	case *FieldError:
+		prefixed := *e
+		prefixed.Path = joinPath(prefix, e.Path)
+		return &prefixed
+	}
+	return newFieldError(prefix, InvalidValueCode, nil, err)
+}
+
+// joinPath joins two parts of a path, the indexes (e.g. "[0]") are joined without dot.
+func joinPath(prefix string, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" {
+		return prefix
+	}
+	if strings.HasPrefix(path, "[") {
+		return prefix + path
+	}
+	return prefix + "." + path
+}
+
+// FieldErrors returns the FieldErrors contained in err (a FieldError, ValidationErrors or an error wrapping them),
+// the errors without structured information are returned as FieldErrors with the code InvalidValueCode.
+func FieldErrors(err error) []*FieldError {
+	if err == nil {
+		return nil
+	}
+	var ve ValidationErrors
+	if errors.As(err, &ve) {
+		fieldErrors := make([]*FieldError, 0, len(ve))
+		for _, e := range ve {
+			fieldErrors = append(fieldErrors, FieldErrors(e)...)
+		}
+		return fieldErrors
+	}
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return []*FieldError{fe}
+	}
+	return []*FieldError{newFieldError("", InvalidValueCode, nil, err)}
+}
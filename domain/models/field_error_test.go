@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+func TestWithPathPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		err    error
+		want   []string
+	}{
+		{name: "nil", prefix: "query", err: nil},
+		{name: "field", prefix: "query", err: NewFieldError("logical", InvalidValueCode, "xor", "invalid"), want: []string{"query.logical"}},
+		{name: "index", prefix: "query.filters", err: NewFieldError("[0].logical", RequiredCode, nil, "required"), want: []string{"query.filters[0].logical"}},
+		{name: "empty path", prefix: "pagination", err: NewFieldError("", InvalidValueCode, nil, "invalid"), want: []string{"pagination"}},
+		{name: "empty prefix", prefix: "", err: NewFieldError("limit", LimitExceededCode, 2000, "exceeded"), want: []string{"limit"}},
+		{
+			name:   "nested validation errors are flattened",
+			prefix: "query",
+			err: ValidationErrors{
+				NewFieldError("logical", RequiredCode, nil, "required"),
+				WithPathPrefix("filters[1]", ValidationErrors{NewFieldError("conditions[0].field", UnknownFieldCode, "x", "unknown")}),
+			},
+			want: []string{"query.logical", "query.filters[1].conditions[0].field"},
+		},
+		{name: "plain error", prefix: "query.sorts[0]", err: errors.New("invalid sort"), want: []string{"query.sorts[0]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, fe := range FieldErrors(WithPathPrefix(tt.prefix, tt.err)) {
+				got = append(got, fe.Path)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	fieldError := NewFieldError("query.logical", InvalidValueCode, "xor", "invalid logical operator: xor")
+	validationErrors := ValidationErrors{
+		fieldError,
+		NewFieldError("pagination.limit", LimitExceededCode, uint(2000), "limit must be less than 1000").WithParam("limit", uint(1000)),
+	}
+	tests := []struct {
+		name      string
+		err       error
+		wantPaths []string
+		wantCodes []ErrorCode
+	}{
+		{name: "nil", err: nil},
+		{name: "field error", err: fieldError, wantPaths: []string{"query.logical"}, wantCodes: []ErrorCode{InvalidValueCode}},
+		{
+			name:      "wrapped validation errors",
+			err:       fmt.Errorf("%w: %w", sentinels.ErrValidation, validationErrors),
+			wantPaths: []string{"query.logical", "pagination.limit"},
+			wantCodes: []ErrorCode{InvalidValueCode, LimitExceededCode},
+		},
+		{name: "plain error", err: errors.New("boom"), wantPaths: []string{""}, wantCodes: []ErrorCode{InvalidValueCode}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FieldErrors(tt.err)
+			if len(got) != len(tt.wantPaths) {
+				t.Fatalf("FieldErrors() = %v, want %d errors", got, len(tt.wantPaths))
+			}
+			for index, fe := range got {
+				if fe.Path != tt.wantPaths[index] || fe.Code != tt.wantCodes[index] {
+					t.Errorf("error[%d] = %s %s, want %s %s", index, fe.Path, fe.Code, tt.wantPaths[index], tt.wantCodes[index])
+				}
+			}
+		})
+	}
+}
+
+func TestFieldErrorsMatchTheValidationSentinel(t *testing.T) {
+	fieldError := NewFieldError("query.logical", InvalidValueCode, "xor", "invalid logical operator: xor")
+	for _, err := range []error{fieldError, ValidationErrors{fieldError}, fmt.Errorf("wrapped: %w", ValidationErrors{fieldError})} {
+		if !errors.Is(err, sentinels.ErrValidation) {
+			t.Errorf("errors.Is(%v, ErrValidation) = false, want true", err)
+		}
+		var fe *FieldError
+		if !errors.As(err, &fe) || fe != fieldError {
+			t.Errorf("errors.As(%v) = %v, want %v", err, fe, fieldError)
+		}
+	}
+}
+
+func TestValidationErrorsJSON(t *testing.T) {
+	err := ValidationErrors{
+		NewFieldError("query.filters[0].conditions[0].field", UnknownFieldCode, "email", "invalid field: email"),
+		NewFieldError("pagination.limit", LimitExceededCode, 2000, "limit must be less than 1000").WithParam("limit", 1000),
+	}
+	got, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+	want := `[{"path":"query.filters[0].conditions[0].field","code":"unknown_field","value":"email","message":"invalid field: email"},` +
+		`{"path":"pagination.limit","code":"limit_exceeded","value":2000,"message":"limit must be less than 1000","params":{"limit":1000}}]`
+	if string(got) != want {
+		t.Errorf("json = %s, want %s", got, want)
+	}
+}
@@ -8,17 +8,16 @@ type Filters []Filter
 // Validate checks the validity of each filter in the collection.
 // It returns a ValidationErrors slice if any filters fail validation.
 func (fs Filters) Validate() error {
+	return fs.validate(nil).orNil()
+}
+
+// validate checks every filter of the collection, when vf is not nil the conditions are checked against the valid fields too.
+func (fs Filters) validate(vf *ValidFields) ValidationErrors {
 	var validationErrors ValidationErrors
 	for index, filter := range fs {
-		if err := filter.Validate(); err != nil {
-			newErr := fmt.Errorf("filter[%v]: %v", index, err)
-			validationErrors = append(validationErrors, newErr)
-		}
-	}
-	if len(validationErrors) > 0 {
-		return validationErrors
+		validationErrors = validationErrors.appendPrefixed(fmt.Sprintf("[%v]", index), filter.validate(vf).orNil())
 	}
-	return nil
+	return validationErrors
 }
 
 func (fs Filters) Len() int { return len(fs) }
@@ -34,29 +33,21 @@ type Filter struct {
 // Validate checks the validity of the filter.
 // It returns a ValidationErrors slice if any validation rules fail.
 func (f Filter) Validate() error {
+	return f.validate(nil).orNil()
+}
+
+// validate checks the filter, when vf is not nil the conditions are checked against the valid fields too.
+func (f Filter) validate(vf *ValidFields) ValidationErrors {
 	var validationErrors ValidationErrors
 
+	// The logical operator is validated when it's present, for more than 1 condition it's required
 	if f.Logical.String() != "" {
 		if err := f.Logical.Validate(); err != nil {
-			validationErrors = append(validationErrors, err)
+			validationErrors = append(validationErrors, newFieldError("logical", InvalidValueCode, f.Logical, err))
 		}
+	} else if len(f.Conditions) > 1 {
+		validationErrors = append(validationErrors, NewFieldError("logical", RequiredCode, nil, "Logical operator is required for more than 1 condition"))
 	}
 
-	if len(f.Conditions) > 1 {
-		if f.Logical.String() == "" {
-			return fmt.Errorf("filter.Logical: Logical operator is required for more than 1 condition")
-		}
-		// If the Logical operator is present we validate if it´s a valid Logical Operator (Check the Logical.Validate() Method)
-		if err := f.Logical.Validate(); err != nil {
-			return err
-		}
-	}
-
-	if err := f.Conditions.Validate(); err != nil {
-		validationErrors = append(validationErrors, err)
-	}
-	if len(validationErrors) > 0 {
-		return validationErrors
-	}
-	return nil
+	return validationErrors.appendPrefixed("conditions", f.Conditions.validate(vf).orNil())
 }
@@ -0,0 +1,190 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// GeoPoint represents a geographic coordinate expressed in latitude and longitude degrees.
+type GeoPoint struct {
+	Lat float64 `json:"lat" example:"-12.1219"`
+	Lon float64 `json:"lon" example:"-77.0297"`
+}
+
+// Validate checks that the latitude is between -90 and 90 and the longitude between -180 and 180.
+func (p GeoPoint) Validate() error {
+	if p.Lat < -90 || p.Lat > 90 {
+		return fmt.Errorf("invalid latitude: %v must be between -90 and 90", p.Lat)
+	}
+	if p.Lon < -180 || p.Lon > 180 {
+		return fmt.Errorf("invalid longitude: %v must be between -180 and 180", p.Lon)
+	}
+	return nil
+}
+
+// DistanceUnit is the unit in which a GeoDistance is expressed.
+type DistanceUnit string
+
+// Predefined distance units.
+const (
+	Meters     DistanceUnit = "m"
+	Kilometers DistanceUnit = "km"
+	Miles      DistanceUnit = "mi"
+)
+
+var metersByUnit = map[DistanceUnit]float64{
+	Meters:     1,
+	Kilometers: 1000,
+	Miles:      1609.344,
+}
+
+// GeoDistance is the value of the geo_distance operator, it matches the points inside of the circle
+// with center in Point and radius Distance.
+type GeoDistance struct {
+	Point    GeoPoint `json:"point"`
+	Distance float64  `json:"distance" example:"5"`
+	// Unit is the unit of the Distance, if it's empty meters are used
+	Unit DistanceUnit `json:"unit" example:"km"`
+}
+
+// Validate checks the validity of the GeoDistance.
+func (gd GeoDistance) Validate() error {
+	if err := gd.Point.Validate(); err != nil {
+		return err
+	}
+	if gd.Distance <= 0 {
+		return errors.New("invalid distance: must be greater than 0")
+	}
+	if gd.Unit != "" {
+		if _, ok := metersByUnit[gd.Unit]; !ok {
+			return fmt.Errorf("invalid distance unit [available:(m,km,mi)]: %s", gd.Unit)
+		}
+	}
+	return nil
+}
+
+// Meters returns the distance converted to meters.
+func (gd GeoDistance) Meters() float64 {
+	if gd.Unit == "" {
+		return gd.Distance
+	}
+	return gd.Distance * metersByUnit[gd.Unit]
+}
+
+// GeoBoundingBox is the value of the geo_bbox operator, it matches the points inside of the box.
+type GeoBoundingBox struct {
+	TopLeft     GeoPoint `json:"top_left"`
+	BottomRight GeoPoint `json:"bottom_right"`
+}
+
+// Validate checks the validity of the GeoBoundingBox.
+func (bb GeoBoundingBox) Validate() error {
+	if err := bb.TopLeft.Validate(); err != nil {
+		return fmt.Errorf("top_left: %v", err)
+	}
+	if err := bb.BottomRight.Validate(); err != nil {
+		return fmt.Errorf("bottom_right: %v", err)
+	}
+	if bb.TopLeft.Lat < bb.BottomRight.Lat {
+		return errors.New("invalid bounding box: top_left latitude must be greater or equals than bottom_right latitude")
+	}
+	// The boxes that cross the antimeridian cannot be translated to a single polygon
+	if bb.TopLeft.Lon > bb.BottomRight.Lon {
+		return errors.New("invalid bounding box: top_left longitude must be less or equals than bottom_right longitude, the boxes cannot cross the antimeridian")
+	}
+	return nil
+}
+
+// GeoPolygon is the value of the geo_polygon operator, it matches the points inside of the polygon.
+type GeoPolygon struct {
+	// Points are the vertices of the polygon, it's not necessary to repeat the first point at the end
+	Points []GeoPoint `json:"points"`
+}
+
+// Validate checks the validity of the GeoPolygon.
+func (gp GeoPolygon) Validate() error {
+	if len(gp.Points) < 3 {
+		return errors.New("invalid polygon: at least 3 points must be specified")
+	}
+	for index, point := range gp.Points {
+		if err := point.Validate(); err != nil {
+			return fmt.Errorf("points[%v]: %v", index, err)
+		}
+	}
+	return nil
+}
+
+// Closed returns the points of the polygon with the first point repeated at the end if it's needed.
+func (gp GeoPolygon) Closed() []GeoPoint {
+	points := append([]GeoPoint{}, gp.Points...)
+	if len(points) > 0 && points[0] != points[len(points)-1] {
+		points = append(points, points[0])
+	}
+	return points
+}
+
+// NewGeoDistance creates a GeoDistance from a condition value, it accepts a GeoDistance or the decoded JSON of one.
+func NewGeoDistance(v interface{}) (GeoDistance, error) {
+	var gd GeoDistance
+	if err := decodeGeoValue(v, &gd); err != nil {
+		return gd, err
+	}
+	return gd, gd.Validate()
+}
+
+// NewGeoBoundingBox creates a GeoBoundingBox from a condition value, it accepts a GeoBoundingBox or the decoded JSON of one.
+func NewGeoBoundingBox(v interface{}) (GeoBoundingBox, error) {
+	var bb GeoBoundingBox
+	if err := decodeGeoValue(v, &bb); err != nil {
+		return bb, err
+	}
+	return bb, bb.Validate()
+}
+
+// NewGeoPolygon creates a GeoPolygon from a condition value, it accepts a GeoPolygon or the decoded JSON of one.
+func NewGeoPolygon(v interface{}) (GeoPolygon, error) {
+	var gp GeoPolygon
+	if err := decodeGeoValue(v, &gp); err != nil {
+		return gp, err
+	}
+	return gp, gp.Validate()
+}
+
+// ValidateGeoValue checks that the value is valid for the given geo operator.
+func ValidateGeoValue(o Operator, v interface{}) error {
+	var err error
+	switch o {
+	case GeoDistanceOperator:
+		_, err = NewGeoDistance(v)
+	case GeoBoundingBoxOperator:
+		_, err = NewGeoBoundingBox(v)
+	case GeoPolygonOperator:
+		_, err = NewGeoPolygon(v)
+	default:
+		err = fmt.Errorf("invalid operator: %s is not a geo operator", o)
+	}
+	return err
+}
+
+// decodeGeoValue decode the value into out, the values that comes from a JSON body are maps
+// so we re-encode them for use the same rules of the json tags
+func decodeGeoValue(v interface{}, out interface{}) error {
+	switch value := v.(type) {
+	case GeoDistance, GeoBoundingBox, GeoPolygon, *GeoDistance, *GeoBoundingBox, *GeoPolygon:
+	case map[string]interface{}:
+	case nil:
+		return errors.New("invalid value: cannot be nil")
+	default:
+		return fmt.Errorf("invalid geo value: %v", value)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("invalid geo value: %v", err)
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("invalid geo value: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IndexKey is a field of an index with its order.
+type IndexKey struct {
+	Field string
+	Order Order
+}
+
+// Index is an index of the entity in the database (e.g. a MongoDB compound index), the conditions can use
+// the index when they filter by its first key and the sorts when they follow the order of its keys.
+type Index struct {
+	Name string
+	Keys []IndexKey
+}
+
+// IsIndexed checks if the conditions of the field (a name or an alias) can use an index. When the entity has
+// Indexes the field must be the first key of one of them, otherwise all the fields are indexed except
+// the fields marked as NotIndexed.
+func (f ValidFields) IsIndexed(s string) bool {
+	fmd, ok := f.Lookup(s)
+	if ok && fmd.NotIndexed {
+		return false
+	}
+	name := s
+	if ok {
+		name = fmd.Field.String()
+	}
+	if len(f.Indexes) == 0 {
+		return ok
+	}
+	for _, index := range f.Indexes {
+		if len(index.Keys) > 0 && index.Keys[0].Field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LintIndexes checks if the conditions and the sorts of the criteria can use the indexes of the entity and
+// returns a warning for every part that cannot use them:
+//
+// - UnindexedFilterCode when none of the filters has a condition on an indexed field (check IsIndexed). For the
+// "or" logical operator every filter (or every condition of the filter) must use an index. The super filters
+// are applied with "and" so an indexed super filter with the "=" or "in" operator is enough.
+//
+// - UnindexedSortCode when the sorts cannot follow the order of an index. With Indexes the sorts must be the
+// keys of an index in the same order (or all of them in the reverse order) after its keys compared by equality,
+// without Indexes the sorts on NotIndexed fields are reported.
+func (c Criteria) LintIndexes(vf ValidFields, superFilters []SuperFilter) []*FieldError {
+	var warnings []*FieldError
+	for _, superFilter := range superFilters {
+		if operator := superFilter.Condition().Operator; (operator.Equals(EqualsOperator) || operator.Equals(InOperator)) && vf.IsIndexed(superFilter.Field) {
+			return append(warnings, c.lintSorts(vf, superFilters)...)
+		}
+	}
+
+	filters := c.Query.Filters
+	if len(filters) > 0 {
+		if c.Query.Logical.Equals(ORLogical) && len(filters) > 1 {
+			for index, filter := range filters {
+				if !filter.usesIndex(vf) {
+					warnings = append(warnings, NewFieldError(fmt.Sprintf("query.filters[%d]", index), UnindexedFilterCode, nil,
+						"the filter cannot use an index, all the filters of an or query must filter by an indexed field"))
+				}
+			}
+		} else if !anyFilterUsesIndex(filters, vf) {
+			warnings = append(warnings, NewFieldError("query.filters", UnindexedFilterCode, nil,
+				"the filters cannot use an index, at least one condition must filter by an indexed field"))
+		}
+	}
+	return append(warnings, c.lintSorts(vf, superFilters)...)
+}
+
+// usesIndex checks if the filter can use an index, with the "or" logical operator all the conditions must use it.
+func (f Filter) usesIndex(vf ValidFields) bool {
+	if f.Logical.Equals(ORLogical) && len(f.Conditions) > 1 {
+		for _, condition := range f.Conditions {
+			if !vf.IsIndexed(condition.Field.String()) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, condition := range f.Conditions {
+		if vf.IsIndexed(condition.Field.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyFilterUsesIndex checks if any of the filters can use an index.
+func anyFilterUsesIndex(filters Filters, vf ValidFields) bool {
+	for _, filter := range filters {
+		if filter.usesIndex(vf) {
+			return true
+		}
+	}
+	return false
+}
+
+// lintSorts returns the warnings of the sorts that cannot use an index.
+func (c Criteria) lintSorts(vf ValidFields, superFilters []SuperFilter) []*FieldError {
+	sorts := c.Query.Sorts
+	if len(sorts) == 0 {
+		return nil
+	}
+
+	if len(vf.Indexes) == 0 {
+		var warnings []*FieldError
+		for index, sort := range sorts {
+			if fmd, ok := vf.Lookup(sort.Field); ok && fmd.NotIndexed {
+				warnings = append(warnings, NewFieldError(fmt.Sprintf("query.sorts[%d].field", index), UnindexedSortCode, sort.Field,
+					"the field "+sort.Field+" is not indexed"))
+			}
+		}
+		return warnings
+	}
+
+	equalities := c.equalityFields(vf, superFilters)
+	for _, index := range vf.Indexes {
+		if index.supportsSorts(vf, sorts, equalities) {
+			return nil
+		}
+	}
+	fields := make([]string, 0, len(sorts))
+	for _, sort := range sorts {
+		fields = append(fields, sort.Field+" "+sort.Order.String())
+	}
+	return []*FieldError{NewFieldError("query.sorts", UnindexedSortCode, nil,
+		"the sort ("+strings.Join(fields, ", ")+") doesn't follow the order of any index")}
+}
+
+// equalityFields returns the fields compared by equality in all the documents of the result: the super filters
+// and the "=" conditions of the filters that are always applied (with the "and" logical operator).
+func (c Criteria) equalityFields(vf ValidFields, superFilters []SuperFilter) map[string]bool {
+	equalities := make(map[string]bool)
+	for _, superFilter := range superFilters {
+		if superFilter.Condition().Operator.Equals(EqualsOperator) {
+			equalities[superFilter.Field] = true
+		}
+	}
+	if c.Query.Logical.Equals(ORLogical) && len(c.Query.Filters) > 1 {
+		return equalities
+	}
+	for _, filter := range c.Query.Filters {
+		if filter.Logical.Equals(ORLogical) && len(filter.Conditions) > 1 {
+			continue
+		}
+		for _, condition := range filter.Conditions {
+			if fmd, ok := vf.Lookup(condition.Field.String()); ok && condition.Operator.Equals(EqualsOperator) {
+				equalities[fmd.Field.String()] = true
+			}
+		}
+	}
+	return equalities
+}
+
+// supportsSorts checks if the sorts follow the keys of the index skipping its first keys compared by equality.
+func (i Index) supportsSorts(vf ValidFields, sorts Sorts, equalities map[string]bool) bool {
+	keys := i.Keys
+	for len(keys) > 0 && equalities[keys[0].Field] && !sortsContain(vf, sorts, keys[0].Field) {
+		keys = keys[1:]
+	}
+	if len(sorts) > len(keys) {
+		return false
+	}
+
+	// The index can be traversed in both directions so the orders must be all equal or all reversed
+	sameOrder, reverseOrder := true, true
+	for index, sort := range sorts {
+		field := sort.Field
+		if fmd, ok := vf.Lookup(sort.Field); ok {
+			field = fmd.Field.String()
+		}
+		if keys[index].Field != field {
+			return false
+		}
+		sameOrder = sameOrder && keys[index].Order.Equals(sort.Order)
+		reverseOrder = reverseOrder && !keys[index].Order.Equals(sort.Order)
+	}
+	return sameOrder || reverseOrder
+}
+
+// sortsContain checks if the field is one of the fields of the sorts.
+func sortsContain(vf ValidFields, sorts Sorts, field string) bool {
+	for _, sort := range sorts {
+		if fmd, ok := vf.Lookup(sort.Field); ok && fmd.Field.String() == field {
+			return true
+		}
+	}
+	return false
+}
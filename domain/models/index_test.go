@@ -0,0 +1,174 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIndexSupportsSorts(t *testing.T) {
+	vf := ValidFields{Fields: map[string]FieldMetaData{
+		"status":     {Type: String, Aliases: []string{"state"}},
+		"created_at": {Type: Date},
+		"_id":        {Type: ObjectID},
+	}}
+	index := Index{Name: "status_created_at_id", Keys: []IndexKey{
+		{Field: "status", Order: ASCOrder},
+		{Field: "created_at", Order: DESCOrder},
+		{Field: "_id", Order: ASCOrder},
+	}}
+
+	tests := []struct {
+		name       string
+		sorts      Sorts
+		equalities []string
+		want       bool
+	}{
+		{name: "first key", sorts: Sorts{{Field: "status", Order: ASCOrder}}, want: true},
+		{name: "prefix", sorts: Sorts{{Field: "status", Order: ASCOrder}, {Field: "created_at", Order: DESCOrder}}, want: true},
+		{name: "all the keys", sorts: Sorts{{Field: "status", Order: ASCOrder}, {Field: "created_at", Order: DESCOrder}, {Field: "_id", Order: ASCOrder}}, want: true},
+		{name: "reversed orders", sorts: Sorts{{Field: "status", Order: DESCOrder}, {Field: "created_at", Order: ASCOrder}}, want: true},
+		{name: "mixed orders", sorts: Sorts{{Field: "status", Order: ASCOrder}, {Field: "created_at", Order: ASCOrder}}, want: false},
+		{name: "alias", sorts: Sorts{{Field: "state", Order: DESCOrder}}, want: true},
+		{name: "not a prefix", sorts: Sorts{{Field: "created_at", Order: DESCOrder}}, want: false},
+		{name: "keys out of order", sorts: Sorts{{Field: "created_at", Order: DESCOrder}, {Field: "status", Order: ASCOrder}}, want: false},
+		{name: "more sorts than keys", sorts: Sorts{{Field: "status", Order: ASCOrder}, {Field: "created_at", Order: DESCOrder}, {Field: "_id", Order: ASCOrder}, {Field: "name", Order: ASCOrder}}, want: false},
+		{name: "equality prefix", sorts: Sorts{{Field: "created_at", Order: DESCOrder}}, equalities: []string{"status"}, want: true},
+		{name: "equality prefix reversed", sorts: Sorts{{Field: "created_at", Order: ASCOrder}, {Field: "_id", Order: DESCOrder}}, equalities: []string{"status"}, want: true},
+		{name: "equality prefix mixed orders", sorts: Sorts{{Field: "created_at", Order: ASCOrder}, {Field: "_id", Order: ASCOrder}}, equalities: []string{"status"}, want: false},
+		{name: "equalities of two keys", sorts: Sorts{{Field: "_id", Order: DESCOrder}}, equalities: []string{"status", "created_at"}, want: true},
+		{name: "equality of a sorted key", sorts: Sorts{{Field: "status", Order: ASCOrder}, {Field: "created_at", Order: DESCOrder}}, equalities: []string{"status"}, want: true},
+		{name: "equality of a later key", sorts: Sorts{{Field: "_id", Order: ASCOrder}}, equalities: []string{"created_at"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equalities := make(map[string]bool)
+			for _, field := range tt.equalities {
+				equalities[field] = true
+			}
+			if got := index.supportsSorts(vf, tt.sorts, equalities); got != tt.want {
+				t.Errorf("supportsSorts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintIndexes(t *testing.T) {
+	fields := map[string]FieldMetaData{
+		"tenant_id":  {Type: String},
+		"status":     {Type: String},
+		"created_at": {Type: Date},
+		"name":       {Type: String, NotIndexed: true},
+		"notes":      {Type: String},
+	}
+	indexed := ValidFields{Fields: fields, Indexes: []Index{
+		{Name: "tenant_status", Keys: []IndexKey{{Field: "tenant_id", Order: ASCOrder}, {Field: "status", Order: ASCOrder}}},
+		{Name: "status_created_at", Keys: []IndexKey{{Field: "status", Order: ASCOrder}, {Field: "created_at", Order: DESCOrder}}},
+	}}
+	flagged := ValidFields{Fields: fields}
+	equals := func(field string, value interface{}) Condition {
+		return Condition{Field: Field(field), Operator: EqualsOperator, Value: value}
+	}
+
+	tests := []struct {
+		name         string
+		vf           ValidFields
+		query        Query
+		superFilters []SuperFilter
+		want         []string
+	}{
+		{name: "no filters", vf: indexed},
+		{
+			name:  "indexed filter",
+			vf:    indexed,
+			query: Query{Filters: Filters{{Conditions: Conditions{equals("notes", "a"), equals("status", "paid")}, Logical: ANDLogical}}},
+		},
+		{
+			name:  "unindexed filter",
+			vf:    indexed,
+			query: Query{Filters: Filters{{Conditions: Conditions{equals("notes", "a")}}}},
+			want:  []string{"query.filters unindexed_filter"},
+		},
+		{
+			name:  "second key of an index",
+			vf:    indexed,
+			query: Query{Filters: Filters{{Conditions: Conditions{equals("created_at", "2024-01-01")}}}},
+			want:  []string{"query.filters unindexed_filter"},
+		},
+		{
+			name: "or query with an unindexed filter",
+			vf:   indexed,
+			query: Query{Logical: ORLogical, Filters: Filters{
+				{Conditions: Conditions{equals("status", "paid")}},
+				{Conditions: Conditions{equals("notes", "a")}},
+			}},
+			want: []string{"query.filters[1] unindexed_filter"},
+		},
+		{
+			name:  "or filter with an unindexed condition",
+			vf:    indexed,
+			query: Query{Filters: Filters{{Logical: ORLogical, Conditions: Conditions{equals("status", "paid"), equals("notes", "a")}}}},
+			want:  []string{"query.filters unindexed_filter"},
+		},
+		{
+			name:         "indexed super filter",
+			vf:           indexed,
+			query:        Query{Filters: Filters{{Conditions: Conditions{equals("notes", "a")}}}},
+			superFilters: []SuperFilter{{Field: "tenant_id", Value: "t1"}},
+		},
+		{
+			name:         "super filter with a range",
+			vf:           indexed,
+			query:        Query{Filters: Filters{{Conditions: Conditions{equals("notes", "a")}}}},
+			superFilters: []SuperFilter{{Field: "tenant_id", Operator: GreaterThan, Value: "t1"}},
+			want:         []string{"query.filters unindexed_filter"},
+		},
+		{
+			name:  "sort after an equality",
+			vf:    indexed,
+			query: Query{Filters: Filters{{Conditions: Conditions{equals("status", "paid")}}}, Sorts: Sorts{{Field: "created_at", Order: ASCOrder}}},
+		},
+		{
+			name:         "sort after a super filter equality",
+			vf:           indexed,
+			query:        Query{Sorts: Sorts{{Field: "status", Order: DESCOrder}}},
+			superFilters: []SuperFilter{{Field: "tenant_id", Value: "t1"}},
+		},
+		{
+			name: "sort after an equality of an or query",
+			vf:   indexed,
+			query: Query{Logical: ORLogical, Filters: Filters{
+				{Conditions: Conditions{equals("status", "paid")}},
+				{Conditions: Conditions{equals("status", "sent")}},
+			}, Sorts: Sorts{{Field: "created_at", Order: ASCOrder}}},
+			want: []string{"query.sorts unindexed_sort"},
+		},
+		{
+			name:  "unindexed sort",
+			vf:    indexed,
+			query: Query{Filters: Filters{{Conditions: Conditions{equals("status", "paid")}}}, Sorts: Sorts{{Field: "notes", Order: ASCOrder}}},
+			want:  []string{"query.sorts unindexed_sort"},
+		},
+		{
+			name:  "not indexed field without indexes",
+			vf:    flagged,
+			query: Query{Filters: Filters{{Conditions: Conditions{equals("name", "a")}}}, Sorts: Sorts{{Field: "notes", Order: ASCOrder}, {Field: "name", Order: ASCOrder}}},
+			want:  []string{"query.filters unindexed_filter", "query.sorts[1].field unindexed_sort"},
+		},
+		{
+			name:  "indexed fields without indexes",
+			vf:    flagged,
+			query: Query{Filters: Filters{{Conditions: Conditions{equals("notes", "a")}}}, Sorts: Sorts{{Field: "created_at", Order: DESCOrder}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, warning := range (Criteria{Query: tt.query}).LintIndexes(tt.vf, tt.superFilters) {
+				got = append(got, warning.Path+" "+string(warning.Code))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("LintIndexes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -23,3 +23,10 @@ func (mq MongoQuery) GetSorts() (bson.M, error) {
 	}
 	return filters, nil
 }
+
+// GetWarnings returns the warnings of the query lint (e.g. the conditions that cannot use an index), it's empty
+// when the query has no warnings.
+func (mq MongoQuery) GetWarnings() []*FieldError {
+	warnings, _ := mq["warnings"].([]*FieldError)
+	return warnings
+}
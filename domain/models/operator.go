@@ -14,15 +14,30 @@ const (
 	LessThan             Operator = "<"
 	GreaterAndEqualsThan Operator = ">="
 	LessAndEqualsThan    Operator = "<="
+	// List operators compare the field against a list of values (e.g. ["a", "b"])
+	InOperator    Operator = "in"
+	NotInOperator Operator = "not_in"
+	// ExistsOperator checks if the field is present (true) or missing (false) in the document
+	ExistsOperator Operator = "exists"
+	// Geo operators only can be applied to fields of type Geo
+	GeoDistanceOperator    Operator = "geo_distance"
+	GeoBoundingBoxOperator Operator = "geo_bbox"
+	GeoPolygonOperator     Operator = "geo_polygon"
 )
 
 var validOperators = map[string]Operator{
-	EqualsOperator.String():       EqualsOperator,
-	NotEqualsOperator.String():    NotEqualsOperator,
-	GreaterThan.String():          GreaterThan,
-	LessThan.String():             LessThan,
-	GreaterAndEqualsThan.String(): GreaterAndEqualsThan,
-	LessAndEqualsThan.String():    LessAndEqualsThan,
+	EqualsOperator.String():         EqualsOperator,
+	NotEqualsOperator.String():      NotEqualsOperator,
+	GreaterThan.String():            GreaterThan,
+	LessThan.String():               LessThan,
+	GreaterAndEqualsThan.String():   GreaterAndEqualsThan,
+	LessAndEqualsThan.String():      LessAndEqualsThan,
+	InOperator.String():             InOperator,
+	NotInOperator.String():          NotInOperator,
+	ExistsOperator.String():         ExistsOperator,
+	GeoDistanceOperator.String():    GeoDistanceOperator,
+	GeoBoundingBoxOperator.String(): GeoBoundingBoxOperator,
+	GeoPolygonOperator.String():     GeoPolygonOperator,
 }
 
 func NewOperator(s string) (Operator, error) {
@@ -37,6 +52,21 @@ func (o Operator) Equals(other Operator) bool {
 	return o.String() == other.String()
 }
 
+// IsGeo checks if the operator is one of the geo-spatial operators.
+func (o Operator) IsGeo() bool {
+	return o.Equals(GeoDistanceOperator) || o.Equals(GeoBoundingBoxOperator) || o.Equals(GeoPolygonOperator)
+}
+
+// IsList checks if the operator compares the field against a list of values.
+func (o Operator) IsList() bool {
+	return o.Equals(InOperator) || o.Equals(NotInOperator)
+}
+
+// IsRange checks if the operator compares the field against a bound (">", "<", ">=" or "<=").
+func (o Operator) IsRange() bool {
+	return o.Equals(GreaterThan) || o.Equals(LessThan) || o.Equals(GreaterAndEqualsThan) || o.Equals(LessAndEqualsThan)
+}
+
 func (o Operator) String() string {
 	return string(o)
 }
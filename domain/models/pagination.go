@@ -1,30 +1,82 @@
 package models
 
 import (
-	"errors"
 	"fmt"
 )
 
 // Pagination represents the structure that indicates pagination to the database
 type Pagination struct {
 	// Limit is the number of items to be returned
-	// - If the limit is 0 or less the default limit value (50) is applied
+	// - If the limit is 0 or less the default limit value (50 or the limit configured for the entity) is applied
 	Limit uint `json:"limit" example:"100" maximum:"1000" minimum:"1"`
 	// Offset is the number of items to be skipped
 	Offset uint `json:"offset" example:"100"`
 }
 
-const MaximumLimit = 1000
-const MaximumLimitOffsetSize uint = 10000
+const (
+	// DefaultPaginationLimit is the limit applied when the pagination limit is 0
+	DefaultPaginationLimit uint = 50
+	// MaximumLimit is the maximum number of items you can retrieve from the database
+	MaximumLimit uint = 1000
+	// MaximumLimitOffsetSize is the maximum value of limit + offset
+	MaximumLimitOffsetSize uint = 10000
+)
+
+// PaginationLimits are the limits applied to the pagination of a criteria.
+// They can be configured per translator (searcher.WithPaginationLimits) and per
+// entity (ValidFields.Limits).
+type PaginationLimits struct {
+	// DefaultLimit is the limit applied when the pagination limit is 0
+	DefaultLimit uint
+	// MaximumLimit is the maximum number of items you can retrieve from the database
+	MaximumLimit uint
+	// MaximumLimitOffsetSize is the maximum value of limit + offset
+	MaximumLimitOffsetSize uint
+}
+
+// DefaultPaginationLimits are the limits applied when no other limits are configured.
+var DefaultPaginationLimits = PaginationLimits{
+	DefaultLimit:           DefaultPaginationLimit,
+	MaximumLimit:           MaximumLimit,
+	MaximumLimitOffsetSize: MaximumLimitOffsetSize,
+}
+
+// WithFallback returns the limits replacing the values that are not set (0) with the values of the fallback limits.
+func (l PaginationLimits) WithFallback(fallback PaginationLimits) PaginationLimits {
+	if l.DefaultLimit == 0 {
+		l.DefaultLimit = fallback.DefaultLimit
+	}
+	if l.MaximumLimit == 0 {
+		l.MaximumLimit = fallback.MaximumLimit
+	}
+	if l.MaximumLimitOffsetSize == 0 {
+		l.MaximumLimitOffsetSize = fallback.MaximumLimitOffsetSize
+	}
+	return l
+}
 
+// Validate checks the pagination against the DefaultPaginationLimits.
 func (p Pagination) Validate() error {
+	return p.ValidateLimits(DefaultPaginationLimits)
+}
+
+// ValidateLimits checks the pagination against the given limits.
+func (p Pagination) ValidateLimits(limits PaginationLimits) error {
+	var validationErrors ValidationErrors
 	// The maximum number of items you can retrieve from the database
-	if p.Limit > MaximumLimit {
-		return errors.New("limit must be less than " + fmt.Sprint(MaximumLimit))
+	if p.Limit > limits.MaximumLimit {
+		validationErrors = append(validationErrors, NewFieldError("limit", LimitExceededCode, p.Limit, "limit must be less than "+fmt.Sprint(limits.MaximumLimit)).
+			WithParam("limit", limits.MaximumLimit))
 	}
 	// This condition is necessary for avoid memory limitations of the database
-	if (p.Limit + p.Offset) > MaximumLimitOffsetSize {
-		return fmt.Errorf(" limit(%d) + offset(%d) must be less or equals %d", p.Limit, p.Offset, MaximumLimitOffsetSize)
+	if (p.Limit + p.Offset) > limits.MaximumLimitOffsetSize {
+		// The limit of the offset is the maximum offset allowed for the requested limit
+		maximumOffset := uint(0)
+		if p.Limit < limits.MaximumLimitOffsetSize {
+			maximumOffset = limits.MaximumLimitOffsetSize - p.Limit
+		}
+		validationErrors = append(validationErrors, NewFieldError("offset", LimitExceededCode, p.Offset, fmt.Sprintf("limit(%d) + offset(%d) must be less or equals %d", p.Limit, p.Offset, limits.MaximumLimitOffsetSize)).
+			WithParam("limit", maximumOffset))
 	}
-	return nil
+	return validationErrors.orNil()
 }
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPaginationLimitsWithFallback(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits PaginationLimits
+		want   PaginationLimits
+	}{
+		{name: "not set", want: DefaultPaginationLimits},
+		{
+			name:   "partially set",
+			limits: PaginationLimits{MaximumLimit: 100},
+			want:   PaginationLimits{DefaultLimit: 50, MaximumLimit: 100, MaximumLimitOffsetSize: 10000},
+		},
+		{
+			name:   "all set",
+			limits: PaginationLimits{DefaultLimit: 10, MaximumLimit: 5000, MaximumLimitOffsetSize: 50000},
+			want:   PaginationLimits{DefaultLimit: 10, MaximumLimit: 5000, MaximumLimitOffsetSize: 50000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.WithFallback(DefaultPaginationLimits); got != tt.want {
+				t.Errorf("WithFallback() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginationValidateLimits(t *testing.T) {
+	limits := PaginationLimits{DefaultLimit: 20, MaximumLimit: 100, MaximumLimitOffsetSize: 500}
+	tests := []struct {
+		name       string
+		pagination Pagination
+		wantErrors []*FieldError
+	}{
+		{name: "within the limits", pagination: Pagination{Limit: 100, Offset: 400}},
+		{
+			name:       "limit exceeded",
+			pagination: Pagination{Limit: 101},
+			wantErrors: []*FieldError{{Path: "limit", Code: LimitExceededCode, Params: map[string]interface{}{"limit": uint(100)}}},
+		},
+		{
+			name:       "offset exceeded",
+			pagination: Pagination{Limit: 100, Offset: 401},
+			wantErrors: []*FieldError{{Path: "offset", Code: LimitExceededCode, Params: map[string]interface{}{"limit": uint(400)}}},
+		},
+		{
+			name:       "both exceeded",
+			pagination: Pagination{Limit: 600},
+			wantErrors: []*FieldError{
+				{Path: "limit", Code: LimitExceededCode, Params: map[string]interface{}{"limit": uint(100)}},
+				{Path: "offset", Code: LimitExceededCode, Params: map[string]interface{}{"limit": uint(0)}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pagination.ValidateLimits(limits)
+			got := FieldErrors(err)
+			if len(got) != len(tt.wantErrors) {
+				t.Fatalf("ValidateLimits() = %v, want %d errors", err, len(tt.wantErrors))
+			}
+			for index, want := range tt.wantErrors {
+				if got[index].Path != want.Path || got[index].Code != want.Code || got[index].Params["limit"] != want.Params["limit"] {
+					t.Errorf("error[%d] = %s %s %v, want %s %s %v", index, got[index].Path, got[index].Code, got[index].Params, want.Path, want.Code, want.Params)
+				}
+			}
+		})
+	}
+}
+
+func TestCriteriaValidateWithLimits(t *testing.T) {
+	criteria := Criteria{Pagination: Pagination{Limit: 200}}
+	if err := criteria.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want the default limits to accept 200", err)
+	}
+	err := criteria.ValidateWithLimits(PaginationLimits{MaximumLimit: 100, MaximumLimitOffsetSize: 1000})
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Path != "pagination.limit" || fe.Code != LimitExceededCode {
+		t.Errorf("ValidateWithLimits() error = %v, want limit_exceeded in pagination.limit", err)
+	}
+}
@@ -1,7 +1,5 @@
 package models
 
-import "fmt"
-
 // Query is the structure that contains the filters and sorts to apply to the search.
 type Query struct {
 	// Filters is an array of filter to apply to the query
@@ -16,30 +14,23 @@ type Query struct {
 }
 
 func (q *Query) Validate() error {
-	if err := q.Filters.Validate(); err != nil {
-		return err
-	}
-	if err := q.Sorts.Validate(); err != nil {
-		return err
-	}
+	return q.validate(nil).orNil()
+}
+
+// validate checks the query, when vf is not nil the conditions and sorts are checked against the valid fields too.
+func (q *Query) validate(vf *ValidFields) ValidationErrors {
+	var validationErrors ValidationErrors
+	validationErrors = validationErrors.appendPrefixed("filters", q.Filters.validate(vf).orNil())
+	validationErrors = validationErrors.appendPrefixed("sorts", q.Sorts.validate(vf).orNil())
 
 	// Validate the logical operator when exist always. Since for 1 filter is optional we must validate if the logical operator is present even if it will be replace in future stages as "AND" like a default operator.
 	if q.Logical.String() != "" {
 		if err := q.Logical.Validate(); err != nil {
-			return fmt.Errorf("criteria.Query.Logical: %v", err)
+			validationErrors = append(validationErrors, newFieldError("logical", InvalidValueCode, q.Logical, err))
 		}
+	} else if len(q.Filters) > 1 {
+		// If the filters are more than one the operator must be always specified
+		validationErrors = append(validationErrors, NewFieldError("logical", RequiredCode, nil, "Logical operator is required for more than 1 filter"))
 	}
-
-	// If the filters are more than one we return an error if the operator is empty for more than one filter must be always specified
-	if len(q.Filters) > 1 {
-		if q.Logical.String() == "" {
-			return fmt.Errorf("criteria.Query.Logical: Logical operator is required for more than 1 filter")
-		}
-		// If the Logical operator is present we validate if it´s a valid Logical Operator (Check the Logical.Validate() Method)
-		if err := q.Logical.Validate(); err != nil {
-			return fmt.Errorf("criteria.Query.Logical: %v", err)
-		}
-	}
-
-	return nil
+	return validationErrors
 }
@@ -0,0 +1,131 @@
+package models
+
+// QueryTree is the intermediate representation of a validated criteria that the dialects translate to the query
+// of their backends. The fields are resolved (the aliases are replaced by the names of the fields), the values are
+// converted (the dates are parsed and the geo values decoded) and the super filters are ANDed with the filters in
+// the Root, check Optimize for the simplifications applied to the tree.
+type QueryTree struct {
+	// Root is the filter of the query, an empty GroupNode matches all the documents
+	Root Node
+	// Sorts are the sorts of the query with their fields resolved
+	Sorts []SortNode
+	// Pagination is the pagination of the criteria with the defaults applied
+	Pagination Pagination
+}
+
+// Node is a node of a QueryTree: a GroupNode, a ConditionNode, a RangeNode, a NotNode or a MatchNoneNode.
+type Node interface {
+	node()
+}
+
+// GroupNode combines its nodes with a logical operator, a group without nodes matches all the documents.
+type GroupNode struct {
+	Logical Logical
+	Nodes   []Node
+}
+
+// ConditionNode is a condition over a field with the "=", "!=", "in", "not_in", "exists" or geo operators, the
+// conditions with range operators are RangeNodes. The values of the Date fields are time.Time values, the Value of
+// the list operators is a []interface{} and the Value of the geo operators is a GeoDistance, a GeoBoundingBox or
+// a GeoPolygon.
+type ConditionNode struct {
+	// Field is the name of the field (never an alias)
+	Field string
+	// MetaData is the metadata of the field, for the super filters over unknown fields only its Field is set
+	MetaData FieldMetaData
+	Operator Operator
+	Value    interface{}
+}
+
+// Bound is a limit of a RangeNode.
+type Bound struct {
+	Value interface{}
+	// Inclusive is true for ">=" and "<=" and false for ">" and "<"
+	Inclusive bool
+}
+
+// RangeNode matches the values of a field between its bounds, a nil bound is not applied (e.g. "> 5" only has Lower).
+type RangeNode struct {
+	// Field is the name of the field (never an alias)
+	Field    string
+	MetaData FieldMetaData
+	Lower    *Bound
+	Upper    *Bound
+}
+
+// NotNode negates its node (e.g. "!= 2024-01-31" is the negation of the range of the day).
+type NotNode struct {
+	Node Node
+}
+
+// MatchNoneNode matches no document, it replaces the groups with contradictory conditions (e.g. a = 1 AND a = 2).
+type MatchNoneNode struct{}
+
+func (GroupNode) node()     {}
+func (ConditionNode) node() {}
+func (RangeNode) node()     {}
+func (NotNode) node()       {}
+func (MatchNoneNode) node() {}
+
+// SortNode is a sort of a QueryTree.
+type SortNode struct {
+	// Field is the name of the field (never an alias)
+	Field    string
+	MetaData FieldMetaData
+	Order    Order
+	// Point is set for the geo fields, the documents are sorted by the distance to it
+	Point *GeoPoint
+}
+
+// NewConditionNode creates the node of a condition over the field of the metadata with a converted value, the
+// range operators create a RangeNode with a single bound and the other operators a ConditionNode.
+func NewConditionNode(fmd FieldMetaData, operator Operator, value interface{}) Node {
+	field := fmd.Field.String()
+	switch operator {
+	case GreaterThan, GreaterAndEqualsThan:
+		return RangeNode{Field: field, MetaData: fmd, Lower: &Bound{Value: value, Inclusive: operator.Equals(GreaterAndEqualsThan)}}
+	case LessThan, LessAndEqualsThan:
+		return RangeNode{Field: field, MetaData: fmd, Upper: &Bound{Value: value, Inclusive: operator.Equals(LessAndEqualsThan)}}
+	}
+	return ConditionNode{Field: field, MetaData: fmd, Operator: operator, Value: value}
+}
+
+// IsMatchAll checks if the node is a group without nodes.
+func IsMatchAll(node Node) bool {
+	group, ok := node.(GroupNode)
+	return ok && len(group.Nodes) == 0
+}
+
+// IsEmpty checks if no value can be between the bounds of the range (e.g. > 5 and < 3), the bounds that cannot
+// be compared are considered not empty.
+func (r RangeNode) IsEmpty() bool {
+	if r.Lower == nil || r.Upper == nil {
+		return false
+	}
+	c, ok := compareValues(r.Lower.Value, r.Upper.Value)
+	return ok && (c > 0 || (c == 0 && !(r.Lower.Inclusive && r.Upper.Inclusive)))
+}
+
+// Contains checks if the value is between the bounds of the range, known is false if the value cannot be
+// compared with the bounds.
+func (r RangeNode) Contains(v interface{}) (contains bool, known bool) {
+	if r.Lower != nil {
+		c, ok := compareValues(v, r.Lower.Value)
+		if !ok {
+			return false, false
+		}
+		if c < 0 || (c == 0 && !r.Lower.Inclusive) {
+			return false, true
+		}
+	}
+	if r.Upper != nil {
+		c, ok := compareValues(v, r.Upper.Value)
+		if !ok {
+			return false, false
+		}
+		if c > 0 || (c == 0 && !r.Upper.Inclusive) {
+			return false, true
+		}
+	}
+	return true, true
+}
@@ -0,0 +1,364 @@
+package models
+
+import (
+	"cmp"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Optimize simplifies a node of a QueryTree without change the documents that it matches, the groups are
+// processed from the leaves to the root with the following passes:
+//
+// - the nested groups with the same logical operator are flattened and the groups with one node are replaced by it
+//
+// - the identical nodes of a group are removed
+//
+// - in "or" groups the "=" and "in" conditions over the same field are merged in a single "in" condition
+//
+// - in "and" groups the ranges over the same field are merged in a single range with the tightest bounds
+//
+// - the "and" groups with contradictory conditions (e.g. a = 1 AND a = 2 or a > 5 AND a < 3) are replaced by a
+// MatchNoneNode, and the MatchNoneNodes are removed from the "or" groups
+//
+// The ranges and the equalities are only merged and compared for the scalar fields (check isScalar), the
+// conditions over an array match the documents with any element that satisfies them (e.g. tags = "a" AND
+// tags = "b" matches ["a", "b"]).
+func Optimize(node Node) Node {
+	switch n := node.(type) {
+	case GroupNode:
+		return optimizeGroup(n)
+	case NotNode:
+		return NotNode{Node: Optimize(n.Node)}
+	case RangeNode:
+		if n.IsEmpty() {
+			return MatchNoneNode{}
+		}
+	}
+	return node
+}
+
+// optimizeGroup applies the passes of Optimize to a group.
+func optimizeGroup(group GroupNode) Node {
+	isOR := group.Logical.Equals(ORLogical)
+
+	nodes := make([]Node, 0, len(group.Nodes))
+	for _, child := range group.Nodes {
+		child = Optimize(child)
+		switch {
+		case IsMatchAll(child):
+			// A node that matches all the documents is neutral in "and" and makes the whole "or" match all of them
+			if isOR {
+				return GroupNode{Logical: ANDLogical}
+			}
+			continue
+		case isMatchNone(child):
+			// A node that matches no document is neutral in "or" and makes the whole "and" match none of them
+			if !isOR {
+				return MatchNoneNode{}
+			}
+			continue
+		}
+		if childGroup, ok := child.(GroupNode); ok && childGroup.Logical.Equals(group.Logical) {
+			nodes = append(nodes, childGroup.Nodes...)
+			continue
+		}
+		nodes = append(nodes, child)
+	}
+	if len(nodes) == 0 {
+		// All the nodes of an "or" matched nothing, the "and" only had neutral nodes
+		if isOR && len(group.Nodes) > 0 {
+			return MatchNoneNode{}
+		}
+		return GroupNode{Logical: ANDLogical}
+	}
+
+	nodes = dedupeNodes(nodes)
+	if isOR {
+		nodes = mergeEqualities(nodes)
+	} else {
+		nodes = mergeRanges(nodes)
+		if hasContradiction(nodes) {
+			return MatchNoneNode{}
+		}
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	return GroupNode{Logical: group.Logical, Nodes: nodes}
+}
+
+// isMatchNone checks if the node is a MatchNoneNode.
+func isMatchNone(node Node) bool {
+	_, ok := node.(MatchNoneNode)
+	return ok
+}
+
+// dedupeNodes removes the nodes equal to a previous node keeping the order.
+func dedupeNodes(nodes []Node) []Node {
+	unique := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !slices.ContainsFunc(unique, func(u Node) bool { return reflect.DeepEqual(u, node) }) {
+			unique = append(unique, node)
+		}
+	}
+	return unique
+}
+
+// mergeEqualities merges the "=" and "in" conditions over the same field of an "or" group in a single "in"
+// condition placed where the first of them was.
+func mergeEqualities(nodes []Node) []Node {
+	merged := make([]Node, 0, len(nodes))
+	positions := make(map[string]int)
+	for _, node := range nodes {
+		condition, ok := node.(ConditionNode)
+		values, mergeable := condition.inValues()
+		if !ok || !mergeable {
+			merged = append(merged, node)
+			continue
+		}
+		position, found := positions[condition.Field]
+		if !found {
+			positions[condition.Field] = len(merged)
+			merged = append(merged, condition)
+			continue
+		}
+		in := merged[position].(ConditionNode)
+		previous, _ := in.inValues()
+		in.Operator = InOperator
+		in.Value = appendUnique(append([]interface{}{}, previous...), values...)
+		merged[position] = in
+	}
+	return merged
+}
+
+// inValues returns the values matched by an "=" or "in" condition, ok is false for the other operators and for
+// the nil values (the equality with null also matches the missing fields).
+func (c ConditionNode) inValues() (values []interface{}, ok bool) {
+	switch {
+	case c.Operator.Equals(EqualsOperator) && c.Value != nil:
+		return []interface{}{c.Value}, true
+	case c.Operator.Equals(InOperator):
+		return ListValues(c.Value)
+	}
+	return nil, false
+}
+
+// appendUnique appends the values that are not already in the list.
+func appendUnique(list []interface{}, values ...interface{}) []interface{} {
+	for _, v := range values {
+		if !slices.ContainsFunc(list, func(l interface{}) bool { return reflect.DeepEqual(l, v) }) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// mergeRanges merges the ranges over the same field of an "and" group, the ranges with bounds that cannot be
+// compared (e.g. a number and a string) are kept apart.
+func mergeRanges(nodes []Node) []Node {
+	merged := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		r, ok := node.(RangeNode)
+		if !ok || !isScalar(r.MetaData) {
+			merged = append(merged, node)
+			continue
+		}
+		position := slices.IndexFunc(merged, func(m Node) bool {
+			other, ok := m.(RangeNode)
+			return ok && other.Field == r.Field && comparableBounds(other.Lower, r.Lower) && comparableBounds(other.Upper, r.Upper)
+		})
+		if position < 0 {
+			merged = append(merged, r)
+			continue
+		}
+		other := merged[position].(RangeNode)
+		other.Lower = tighterBound(other.Lower, r.Lower, 1)
+		other.Upper = tighterBound(other.Upper, r.Upper, -1)
+		merged[position] = other
+	}
+	return merged
+}
+
+// comparableBounds checks if two bounds can be compared, the nil bounds can be compared with any bound.
+func comparableBounds(a *Bound, b *Bound) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	_, ok := compareValues(a.Value, b.Value)
+	return ok
+}
+
+// tighterBound returns the most restrictive of two comparable bounds, direction is 1 for the lower bounds
+// (the greatest wins) and -1 for the upper bounds (the smallest wins). With equal values the exclusive bound wins.
+func tighterBound(a *Bound, b *Bound, direction int) *Bound {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	c, _ := compareValues(a.Value, b.Value)
+	switch {
+	case c*direction > 0:
+		return a
+	case c*direction < 0:
+		return b
+	}
+	return &Bound{Value: a.Value, Inclusive: a.Inclusive && b.Inclusive}
+}
+
+// hasContradiction checks if no document can match all the nodes of an "and" group.
+func hasContradiction(nodes []Node) bool {
+	for i, a := range nodes {
+		if r, ok := a.(RangeNode); ok && r.IsEmpty() {
+			return true
+		}
+		for _, b := range nodes[i+1:] {
+			if contradicts(a, b) || contradicts(b, a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// contradicts checks if no document can match the conditions a and b over the same field, the values that cannot
+// be compared are never considered contradictory.
+func contradicts(a Node, b Node) bool {
+	condition, ok := a.(ConditionNode)
+	if !ok {
+		return false
+	}
+
+	if r, ok := b.(RangeNode); ok {
+		if r.Field != condition.Field {
+			return false
+		}
+		if condition.Operator.Equals(ExistsOperator) {
+			return condition.Value == false
+		}
+		if !condition.Operator.Equals(EqualsOperator) || condition.Value == nil || !isScalar(condition.MetaData) {
+			return false
+		}
+		// The ranges compare the strings with their case so they cannot be compared with the case insensitive equality
+		if _, isString := condition.Value.(string); isString && condition.MetaData.IsCaseInsensitive {
+			return false
+		}
+		contains, known := r.Contains(condition.Value)
+		return known && !contains
+	}
+
+	other, ok := b.(ConditionNode)
+	if !ok || other.Field != condition.Field {
+		return false
+	}
+	switch {
+	case condition.Operator.Equals(ExistsOperator) && other.Operator.Equals(ExistsOperator):
+		return condition.Value != other.Value
+	case condition.Operator.Equals(ExistsOperator):
+		// A missing field cannot be equal to a value
+		return condition.Value == false && other.Operator.Equals(EqualsOperator) && other.Value != nil
+	case condition.Operator.Equals(EqualsOperator) && other.Operator.Equals(NotEqualsOperator):
+		equal, known := sameValue(condition.MetaData, condition.Value, other.Value)
+		return known && equal
+	case condition.Operator.Equals(NotInOperator) && other.Operator.Equals(EqualsOperator):
+		values, _ := ListValues(condition.Value)
+		return slices.ContainsFunc(values, func(v interface{}) bool {
+			equal, known := sameValue(condition.MetaData, v, other.Value)
+			return known && equal
+		})
+	}
+
+	// Two "=" or "in" conditions contradict each other when all their values are different (an array can
+	// contain both of them)
+	if !isScalar(condition.MetaData) {
+		return false
+	}
+	values, ok := condition.inValues()
+	otherValues, otherOk := other.inValues()
+	if !ok || !otherOk {
+		return false
+	}
+	for _, v := range values {
+		for _, o := range otherValues {
+			if equal, known := sameValue(condition.MetaData, v, o); equal || !known {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isScalar checks if the field holds a single value, the multi-valued fields and the fields that are not
+// registered (e.g. of the super filters) can hold arrays.
+func isScalar(fmd FieldMetaData) bool {
+	return fmd.Type != "" && !fmd.MultiValued
+}
+
+// sameValue checks if two values of a field are equal, the strings of the case insensitive fields are compared
+// ignoring the case. known is false if the values cannot be compared (e.g. a number and a string).
+func sameValue(fmd FieldMetaData, a interface{}, b interface{}) (equal bool, known bool) {
+	if x, ok := a.(string); ok && fmd.IsCaseInsensitive {
+		if y, ok := b.(string); ok {
+			return strings.EqualFold(x, y), true
+		}
+	}
+	if x, ok := a.(bool); ok {
+		y, ok := b.(bool)
+		return x == y, ok
+	}
+	c, ok := compareValues(a, b)
+	return c == 0, ok
+}
+
+// compareValues compares two numbers, dates or strings, ok is false if the values are not of the same kind.
+func compareValues(a interface{}, b interface{}) (c int, ok bool) {
+	switch x := a.(type) {
+	case time.Time:
+		y, ok := b.(time.Time)
+		if !ok {
+			return 0, false
+		}
+		return x.Compare(y), true
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	}
+	x, ok := toFloat(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := toFloat(b)
+	if !ok {
+		return 0, false
+	}
+	return cmp.Compare(x, y), true
+}
+
+// toFloat converts the numbers of any type (including json.Number) to float64.
+func toFloat(v interface{}) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	if v == nil {
+		return 0, false
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+	return 0, false
+}
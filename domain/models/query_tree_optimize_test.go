@@ -0,0 +1,150 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptimize(t *testing.T) {
+	amount := FieldMetaData{Field: "amount", Type: Number}
+	status := FieldMetaData{Field: "status", Type: String}
+	name := FieldMetaData{Field: "name", Type: String, IsCaseInsensitive: true}
+	tags := FieldMetaData{Field: "tags", Type: String, MultiValued: true}
+	tenant := FieldMetaData{Field: "tenant_id"}
+
+	cond := NewConditionNode
+	and := func(nodes ...Node) GroupNode { return GroupNode{Logical: ANDLogical, Nodes: nodes} }
+	or := func(nodes ...Node) GroupNode { return GroupNode{Logical: ORLogical, Nodes: nodes} }
+	between := func(fmd FieldMetaData, lower *Bound, upper *Bound) RangeNode {
+		return RangeNode{Field: fmd.Field.String(), MetaData: fmd, Lower: lower, Upper: upper}
+	}
+
+	tests := []struct {
+		name string
+		node Node
+		want Node
+	}{
+		{
+			name: "flatten nested groups with the same logical",
+			node: and(cond(status, EqualsOperator, "a"), and(cond(amount, EqualsOperator, 1), and(cond(name, EqualsOperator, "x")))),
+			want: and(cond(status, EqualsOperator, "a"), cond(amount, EqualsOperator, 1), cond(name, EqualsOperator, "x")),
+		},
+		{
+			name: "keep nested groups with another logical",
+			node: and(cond(amount, EqualsOperator, 1), or(cond(status, EqualsOperator, "a"), cond(name, EqualsOperator, "x"))),
+			want: and(cond(amount, EqualsOperator, 1), or(cond(status, EqualsOperator, "a"), cond(name, EqualsOperator, "x"))),
+		},
+		{
+			name: "replace single node groups",
+			node: and(or(cond(status, EqualsOperator, "a"))),
+			want: cond(status, EqualsOperator, "a"),
+		},
+		{
+			name: "dedupe identical nodes",
+			node: and(cond(status, EqualsOperator, "a"), cond(amount, EqualsOperator, 1), cond(status, EqualsOperator, "a")),
+			want: and(cond(status, EqualsOperator, "a"), cond(amount, EqualsOperator, 1)),
+		},
+		{
+			name: "merge equalities in an in condition",
+			node: or(cond(status, EqualsOperator, "a"), cond(amount, EqualsOperator, 1), cond(status, InOperator, []interface{}{"b", "a"})),
+			want: or(cond(status, InOperator, []interface{}{"a", "b"}), cond(amount, EqualsOperator, 1)),
+		},
+		{
+			name: "do not merge equalities with null",
+			node: or(cond(status, EqualsOperator, nil), cond(status, EqualsOperator, "a")),
+			want: or(cond(status, EqualsOperator, nil), cond(status, EqualsOperator, "a")),
+		},
+		{
+			name: "do not merge equalities under and",
+			node: and(cond(status, EqualsOperator, "a"), cond(status, InOperator, []interface{}{"a", "b"})),
+			want: and(cond(status, EqualsOperator, "a"), cond(status, InOperator, []interface{}{"a", "b"})),
+		},
+		{
+			name: "merge ranges under and",
+			node: and(cond(amount, GreaterAndEqualsThan, 5), cond(amount, GreaterThan, 7), cond(amount, LessAndEqualsThan, 10)),
+			want: between(amount, &Bound{Value: 7}, &Bound{Value: 10, Inclusive: true}),
+		},
+		{
+			name: "contradictory equalities",
+			node: and(cond(status, EqualsOperator, "a"), cond(status, EqualsOperator, "b")),
+			want: MatchNoneNode{},
+		},
+		{
+			name: "contradictory in conditions",
+			node: and(cond(status, InOperator, []interface{}{"a", "b"}), cond(status, InOperator, []interface{}{"c"})),
+			want: MatchNoneNode{},
+		},
+		{
+			name: "contradictory equality and not equals",
+			node: and(cond(status, EqualsOperator, "a"), cond(status, NotEqualsOperator, "a")),
+			want: MatchNoneNode{},
+		},
+		{
+			name: "contradictory equality and not in",
+			node: and(cond(status, NotInOperator, []interface{}{"a", "b"}), cond(status, EqualsOperator, "b")),
+			want: MatchNoneNode{},
+		},
+		{
+			name: "contradictory exists",
+			node: and(cond(status, ExistsOperator, false), cond(status, EqualsOperator, "a")),
+			want: MatchNoneNode{},
+		},
+		{
+			name: "contradictory ranges",
+			node: and(cond(amount, GreaterThan, 10), cond(amount, LessThan, 5)),
+			want: MatchNoneNode{},
+		},
+		{
+			name: "equality outside of a range",
+			node: and(cond(amount, EqualsOperator, 1), cond(amount, GreaterThan, 5)),
+			want: MatchNoneNode{},
+		},
+		{
+			name: "contradictory group removed from or",
+			node: or(cond(amount, EqualsOperator, 1), and(cond(status, EqualsOperator, "a"), cond(status, EqualsOperator, "b"))),
+			want: cond(amount, EqualsOperator, 1),
+		},
+		{
+			name: "case insensitive equalities",
+			node: and(cond(name, EqualsOperator, "Ana"), cond(name, EqualsOperator, "ana")),
+			want: and(cond(name, EqualsOperator, "Ana"), cond(name, EqualsOperator, "ana")),
+		},
+		{
+			name: "case insensitive equality against a range",
+			node: and(cond(name, EqualsOperator, "ana"), cond(name, LessThan, "B")),
+			want: and(cond(name, EqualsOperator, "ana"), cond(name, LessThan, "B")),
+		},
+		{
+			name: "multi valued equalities",
+			node: and(cond(tags, EqualsOperator, "a"), cond(tags, EqualsOperator, "b")),
+			want: and(cond(tags, EqualsOperator, "a"), cond(tags, EqualsOperator, "b")),
+		},
+		{
+			name: "multi valued ranges",
+			node: and(cond(tags, GreaterThan, "m"), cond(tags, LessThan, "c"), cond(tags, EqualsOperator, "a")),
+			want: and(cond(tags, GreaterThan, "m"), cond(tags, LessThan, "c"), cond(tags, EqualsOperator, "a")),
+		},
+		{
+			name: "multi valued missing field",
+			node: and(cond(tags, ExistsOperator, false), cond(tags, EqualsOperator, "a")),
+			want: MatchNoneNode{},
+		},
+		{
+			name: "multi valued equalities merged under or",
+			node: or(cond(tags, EqualsOperator, "a"), cond(tags, EqualsOperator, "b")),
+			want: cond(tags, InOperator, []interface{}{"a", "b"}),
+		},
+		{
+			name: "unregistered field equalities",
+			node: and(cond(tenant, EqualsOperator, "t1"), cond(tenant, EqualsOperator, "t2")),
+			want: and(cond(tenant, EqualsOperator, "t1"), cond(tenant, EqualsOperator, "t2")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Optimize(tt.node); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Optimize() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,33 +1,65 @@
 package models
 
 import (
-	"errors"
 	"fmt"
 )
 
 type Sorts []Sort
 
 func (ss Sorts) Validate() error {
+	return ss.validate(nil).orNil()
+}
+
+// validate checks every sort of the collection, when vf is not nil the sorts are checked against the valid fields too.
+func (ss Sorts) validate(vf *ValidFields) ValidationErrors {
+	var validationErrors ValidationErrors
+	distanceSorts := 0
 	for index, sort := range ss {
-		if err := sort.Validate(); err != nil {
-			newErr := fmt.Errorf("sorts[%v]: %v", index, err)
-			return newErr
+		validationErrors = validationErrors.appendPrefixed(fmt.Sprintf("[%v]", index), sort.validate(vf).orNil())
+		// The databases only can sort by the distance to one point (MongoDB rejects more than one $nearSphere)
+		if sort.Point != nil {
+			distanceSorts++
+			if distanceSorts > 1 {
+				validationErrors = append(validationErrors, NewFieldError(fmt.Sprintf("[%v].point", index), InvalidValueCode, sort.Point, "invalid sort: only one sort by distance is allowed"))
+			}
 		}
 	}
-	return nil
+	return validationErrors
 }
 
 type Sort struct {
 	Field string
 	Order Order
+	// Point is required when the Field is of type Geo, the results are sorted by the distance to this point
+	Point *GeoPoint `json:"point,omitempty"`
 }
 
 func (s Sort) Validate() error {
+	return s.validate(nil).orNil()
+}
+
+// validate checks the sort, when vf is not nil the field of the sort is checked against the valid fields too.
+func (s Sort) validate(vf *ValidFields) ValidationErrors {
+	var validationErrors ValidationErrors
 	if s.Field == "" {
-		return errors.New("invalid field: empty")
+		validationErrors = append(validationErrors, NewFieldError("field", RequiredCode, nil, "invalid field: empty"))
 	}
 	if err := s.Order.Validate(); err != nil {
-		return err
+		validationErrors = append(validationErrors, newFieldError("order", InvalidValueCode, s.Order, err))
+	}
+	if s.Point != nil {
+		if err := s.Point.Validate(); err != nil {
+			validationErrors = append(validationErrors, newFieldError("point", InvalidValueCode, s.Point, err))
+		}
+	}
+
+	if vf != nil && s.Field != "" {
+		fieldMetaData, ok := vf.SortField(s.Field)
+		if !ok {
+			validationErrors = append(validationErrors, NewFieldError("field", UnknownFieldCode, s.Field, "invalid field: "+s.Field))
+		} else if fieldMetaData.Type.Equals(Geo) != (s.Point != nil) {
+			validationErrors = append(validationErrors, NewFieldError("point", InvalidValueCode, s.Point, "invalid sort: "+s.Field+" the point is required only for geo fields"))
+		}
 	}
-	return nil
+	return validationErrors
 }
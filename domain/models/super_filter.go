@@ -1,7 +1,34 @@
 package models
 
-// SuperFilter are filters that will be applied in the top of your query ignoring validation for permitted search fields it can only be applied as a equals filter
+// SuperFilter are filters that will be applied in the top of your query ignoring validation for permitted search fields,
+// they are translated as the conditions (e.g. tenant_id in ["a", "b"] or deleted_at exists false) and the Operator is "=" when it's empty
 type SuperFilter struct {
-	Field string      `bson:"field"`
-	Value interface{} `bson:"value"`
+	Field    string      `bson:"field"`
+	Operator Operator    `bson:"operator,omitempty"`
+	Value    interface{} `bson:"value"`
+}
+
+// Condition returns the condition equivalent to the super filter.
+func (sf SuperFilter) Condition() Condition {
+	operator := sf.Operator
+	if operator.String() == "" {
+		operator = EqualsOperator
+	}
+	return Condition{Field: Field(sf.Field), Operator: operator, Value: sf.Value}
+}
+
+// Validate checks the field, the operator and the value of the super filter as Condition.Validate does, unlike
+// the conditions the nil value is accepted by the "=" and "!=" operators (it matches the null or missing fields).
+func (sf SuperFilter) Validate() error {
+	condition := sf.Condition()
+	allowsNil := condition.Value == nil && (condition.Operator.Equals(EqualsOperator) || condition.Operator.Equals(NotEqualsOperator))
+
+	var validationErrors ValidationErrors
+	for _, err := range condition.validate(nil) {
+		if fe, ok := err.(*FieldError); ok && allowsNil && fe.Path == "value" && fe.Code == RequiredCode {
+			continue
+		}
+		validationErrors = append(validationErrors, err)
+	}
+	return validationErrors.orNil()
 }
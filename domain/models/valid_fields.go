@@ -3,6 +3,65 @@ package models
 type ValidFields struct {
 	EntityName string
 	Fields     map[string]FieldMetaData
+	// Limits are the pagination limits of the entity, the values that are not set (0)
+	// are taken from the limits of the translator
+	Limits PaginationLimits
+	// Complexity are the complexity limits of the entity, the values that are not set (0)
+	// are taken from the limits of the translator
+	Complexity ComplexityLimits
+	// Indexes are the indexes of the entity in the database used for lint the criteria (check Criteria.LintIndexes),
+	// without indexes all the fields are considered indexed except the fields marked as NotIndexed
+	Indexes []Index
+}
+
+// Lookup returns the metadata of a field by its name or any of its aliases, the Field of
+// the returned metadata is always the name under which the field is registered.
+func (f ValidFields) Lookup(s string) (FieldMetaData, bool) {
+	fmd, ok := f.Fields[s]
+	if ok {
+		fmd.Field = Field(s)
+		return fmd, true
+	}
+	for name, fmd := range f.Fields {
+		for _, alias := range fmd.Aliases {
+			if alias == s {
+				fmd.Field = Field(name)
+				return fmd, true
+			}
+		}
+	}
+	return FieldMetaData{}, false
+}
+
+// ForRoles returns a copy of the valid fields with only the fields visible for the caller with the roles (check
+// FieldMetaData.VisibleTo), the other fields are rejected as unknown fields by the validations and translators.
+func (f ValidFields) ForRoles(roles []string) ValidFields {
+	fields := make(map[string]FieldMetaData, len(f.Fields))
+	for name, fmd := range f.Fields {
+		if fmd.VisibleTo(roles) {
+			fields[name] = fmd
+		}
+	}
+	f.Fields = fields
+	return f
+}
+
+// FilterField returns the metadata of a field that can be used in the conditions (check Lookup).
+func (f ValidFields) FilterField(s string) (FieldMetaData, bool) {
+	fmd, ok := f.Lookup(s)
+	if !ok || fmd.NotFilterable {
+		return FieldMetaData{}, false
+	}
+	return fmd, true
+}
+
+// SortField returns the metadata of a field that can be used in the sorts (check Lookup).
+func (f ValidFields) SortField(s string) (FieldMetaData, bool) {
+	fmd, ok := f.Lookup(s)
+	if !ok || fmd.NotSortable {
+		return FieldMetaData{}, false
+	}
+	return fmd, true
 }
 
 func (f ValidFields) GetFieldType(s string) FieldType {
@@ -20,3 +79,11 @@ func (f ValidFields) IsAnalyzed(s string) bool {
 	}
 	return fmd.IsAnalyzed
 }
+
+func (f ValidFields) IsCaseInsensitive(s string) bool {
+	fmd, ok := f.Fields[s]
+	if !ok {
+		return false
+	}
+	return fmd.IsCaseInsensitive
+}
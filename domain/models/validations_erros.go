@@ -1,7 +1,10 @@
 package models
 
 import (
+	"encoding/json"
 	"strings"
+
+	"github.com/solrac97gr/searcher/internal/sentinels"
 )
 
 // ValidationErrors represents a slice of errors that occurred during validation.
@@ -15,3 +18,39 @@ func (ve ValidationErrors) Error() string {
 	}
 	return strings.Join(errorMessages, ", ")
 }
+
+// Unwrap returns the validation errors for errors.Is and errors.As (e.g. for get the first FieldError).
+func (ve ValidationErrors) Unwrap() []error {
+	return ve
+}
+
+// Is reports that the ValidationErrors are validation errors.
+func (ve ValidationErrors) Is(target error) bool {
+	return target == sentinels.ErrValidation
+}
+
+// MarshalJSON encodes the validation errors as a list of FieldErrors.
+func (ve ValidationErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(FieldErrors(ve))
+}
+
+// appendPrefixed appends err with the prefix added to the path of its FieldErrors (check WithPathPrefix),
+// the ValidationErrors are flattened so the result only contains FieldErrors.
+func (ve ValidationErrors) appendPrefixed(prefix string, err error) ValidationErrors {
+	switch prefixed := WithPathPrefix(prefix, err).(type) {
+	case nil:
+		return ve
+	case ValidationErrors:
+		return append(ve, prefixed...)
+	default:
+		return append(ve, prefixed)
+	}
+}
+
+// orNil returns nil when there are no errors, for avoid returning a non nil error with an empty slice.
+func (ve ValidationErrors) orNil() error {
+	if len(ve) == 0 {
+		return nil
+	}
+	return ve
+}
@@ -1,6 +1,8 @@
 package ports
 
 import (
+	"context"
+
 	"github.com/solrac97gr/searcher/domain/models"
 )
 
@@ -13,7 +15,7 @@ type QueryTranslator interface {
 	//
 	// - rawCriteria: A Criteria object to convert to a MongoDB query
 	//
-	// - superFilters: A list of filters to apply in top-level of the query that only will allow equals operator and will skip validation of valid filters for client.
+	// - superFilters: A list of filters to apply in top-level of the query translated with the same operators as the conditions that will skip validation of valid filters for client.
 	//
 	// If there is an error during conversion, it returns an error.
 	ToMongo(validMapEntityName string, rawCriteria models.Criteria, superFilters []models.SuperFilter) (models.MongoQuery, error)
@@ -25,7 +27,7 @@ type QueryTranslator interface {
 	//
 	// - rawCriteria: A Criteria object to convert to a Elastic query
 	//
-	// - superFilters: A list of filters to apply in top-level of the query that only will allow equals operator and will skip validation of valid filters for client.
+	// - superFilters: A list of filters to apply in top-level of the query translated with the same operators as the conditions that will skip validation of valid filters for client.
 	//
 	// If there is an error during conversion, it returns an error.
 	ToElastic(validMapEntityName string, criteria models.Criteria, superFilters []models.SuperFilter) (string, error)
@@ -33,3 +35,16 @@ type QueryTranslator interface {
 	// SetValidFields
 	AddValidFieldsSet(validFields models.ValidFields) error
 }
+
+// ContextQueryTranslator is a QueryTranslator that also translates with a context, it's a separate interface so
+// the implementations of QueryTranslator don't need the context methods.
+type ContextQueryTranslator interface {
+	QueryTranslator
+
+	// ToMongoCtx is ToMongo with a context, the context carries the deadline of the request, the principal
+	// used by the policies and the field visibility, the locale of the errors and the tracing span.
+	ToMongoCtx(ctx context.Context, validMapEntityName string, rawCriteria models.Criteria, superFilters []models.SuperFilter) (models.MongoQuery, error)
+
+	// ToElasticCtx is ToElastic with a context (check ToMongoCtx).
+	ToElasticCtx(ctx context.Context, validMapEntityName string, criteria models.Criteria, superFilters []models.SuperFilter) (string, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+// DateFormatter exposes methods for date manipulation, the QueryTranslator use it
+// for convert the values of the conditions over Date fields.
+//
+// The default implementation is date.Formatter but you can provide your own
+// implementation using the searcher.WithDateFormatter option.
+type DateFormatter interface {
+	// FromISO8601String parses a date in ISO8601 format and returns it in UTC.
+	FromISO8601String(string) (*time.Time, error)
+	// FromRelativeString resolves a relative date expression (e.g. "now-7d") and returns it in UTC.
+	FromRelativeString(string) (*time.Time, error)
+	// Parse accepts the values of a date condition: absolute dates in any of
+	// the accepted formats, relative date expressions and epoch timestamps.
+	Parse(interface{}) (models.DateValue, error)
+}
@@ -0,0 +1,46 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+// TranslationRequest is the input of a Dialect, the QueryTranslator validates and normalizes the criteria
+// before build it so the dialects only need to convert it to the query of their backend. The dialects should
+// translate the Tree, the Criteria is kept for the parts of the query that are not in the tree.
+type TranslationRequest struct {
+	// EntityName is the name of the entity of the criteria
+	EntityName string
+	// Fields are the valid fields of the entity visible for the caller, with the limits of the translator resolved
+	Fields models.ValidFields
+	// Criteria is the criteria prepared with the defaults and validated against the Fields
+	Criteria models.Criteria
+	// SuperFilters are the validated super filters including the filters of the policies of the entity
+	SuperFilters []models.SuperFilter
+	// Tree is the optimized intermediate representation of the Criteria and the SuperFilters (check models.QueryTree)
+	Tree models.QueryTree
+	// DateFormatter is the formatter of the translator for convert the values of the Date fields
+	DateFormatter DateFormatter
+}
+
+// Dialect converts a validated criteria to the query of a backend (e.g. MongoDB, Elasticsearch or SQL),
+// the dialects are registered by name in the QueryTranslator (check Translator).
+type Dialect interface {
+	// Name is the name used for select the dialect (e.g. "mongo")
+	Name() string
+	// Translate returns the query of the backend for the request, the errors related to a part of the
+	// criteria should be models.FieldError values for be reported to the caller
+	Translate(ctx context.Context, request TranslationRequest) (interface{}, error)
+}
+
+// Translator translates the criteria with the dialects registered by name, a new backend is added registering
+// its Dialect without change the interfaces.
+type Translator interface {
+	// Translate converts the criteria of the entity with the dialect, the super filters are applied at the
+	// top level of the query as in QueryTranslator.ToMongo.
+	Translate(ctx context.Context, dialect string, validMapEntityName string, criteria models.Criteria, superFilters ...models.SuperFilter) (interface{}, error)
+
+	// RegisterDialect adds a dialect, it fails if there is a dialect with the same name.
+	RegisterDialect(dialect Dialect) error
+}
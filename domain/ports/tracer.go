@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// Tracer is notified of every translation of the QueryTranslator, it can be used for create tracing spans or
+// record metrics of the translations (e.g. an OpenTelemetry tracer) using the searcher.WithTracer option.
+type Tracer interface {
+	// StartTranslation is called before translate a criteria of the entity for the backend (e.g. "mongo" or
+	// "elastic"), the returned context is used by the translation (e.g. the context with the span) and the
+	// returned function is called with the result of the translation.
+	StartTranslation(ctx context.Context, backend string, entityName string) (context.Context, func(err error))
+}
@@ -0,0 +1,156 @@
+package searcher_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+func TestValidationFieldErrors(t *testing.T) {
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "clients",
+		Fields: map[string]models.FieldMetaData{
+			"name":       {Type: models.String},
+			"age":        {Type: models.Number},
+			"created_at": {Type: models.Date},
+			"id":         {Type: models.ObjectID},
+			"active":     {Type: models.Boolean},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	condition := func(conditions ...models.Condition) models.Criteria {
+		return models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: conditions}}}}
+	}
+	type fieldError struct {
+		path string
+		code models.ErrorCode
+	}
+
+	tests := []struct {
+		name         string
+		criteria     models.Criteria
+		superFilters []models.SuperFilter
+		want         []fieldError
+	}{
+		{
+			name:     "unknown field",
+			criteria: condition(models.Condition{Field: "email", Operator: models.EqualsOperator, Value: "a"}),
+			want:     []fieldError{{"query.filters[0].conditions[0].field", models.UnknownFieldCode}},
+		},
+		{
+			name:     "unknown operator",
+			criteria: condition(models.Condition{Field: "name", Operator: "~", Value: "a"}),
+			want:     []fieldError{{"query.filters[0].conditions[0].operator", models.InvalidOperatorCode}},
+		},
+		{
+			name:     "operator not allowed for the field",
+			criteria: condition(models.Condition{Field: "active", Operator: models.GreaterThan, Value: true}),
+			want:     []fieldError{{"query.filters[0].conditions[0].operator", models.InvalidOperatorCode}},
+		},
+		{
+			name:     "missing value",
+			criteria: condition(models.Condition{Field: "name", Operator: models.EqualsOperator}),
+			want:     []fieldError{{"query.filters[0].conditions[0].value", models.RequiredCode}},
+		},
+		{
+			name:     "string for a number range",
+			criteria: condition(models.Condition{Field: "age", Operator: models.GreaterThan, Value: "5"}),
+			want:     []fieldError{{"query.filters[0].conditions[0].value", models.TypeMismatchCode}},
+		},
+		{
+			name:     "invalid date",
+			criteria: condition(models.Condition{Field: "created_at", Operator: models.GreaterThan, Value: "yesterday"}),
+			want:     []fieldError{{"query.filters[0].conditions[0].value", models.TypeMismatchCode}},
+		},
+		{
+			name:     "invalid object id in a list",
+			criteria: condition(models.Condition{Field: "id", Operator: models.InOperator, Value: []string{"65a1b2c3d4e5f60718293a4b", "bad"}}),
+			want:     []fieldError{{"query.filters[0].conditions[0].value[1]", models.TypeMismatchCode}},
+		},
+		{
+			name:     "empty list",
+			criteria: condition(models.Condition{Field: "name", Operator: models.InOperator, Value: []string{}}),
+			want:     []fieldError{{"query.filters[0].conditions[0].value", models.InvalidValueCode}},
+		},
+		{
+			name: "missing logical of a filter",
+			criteria: condition(
+				models.Condition{Field: "name", Operator: models.EqualsOperator, Value: "a"},
+				models.Condition{Field: "name", Operator: models.EqualsOperator, Value: "b"},
+			),
+			want: []fieldError{{"query.filters[0].logical", models.RequiredCode}},
+		},
+		{
+			name: "invalid logical of the query",
+			criteria: models.Criteria{Query: models.Query{Logical: "xor", Filters: models.Filters{
+				{Conditions: models.Conditions{{Field: "name", Operator: models.EqualsOperator, Value: "a"}}},
+				{Conditions: models.Conditions{{Field: "age", Operator: models.EqualsOperator, Value: 1}}},
+			}}},
+			want: []fieldError{{"query.logical", models.InvalidValueCode}},
+		},
+		{
+			name:     "invalid sorts",
+			criteria: models.Criteria{Query: models.Query{Sorts: models.Sorts{{Field: "email", Order: models.ASCOrder}, {Field: "name", Order: "up"}}}},
+			want: []fieldError{
+				{"query.sorts[0].field", models.UnknownFieldCode},
+				{"query.sorts[1].order", models.InvalidValueCode},
+			},
+		},
+		{
+			name:     "limit exceeded",
+			criteria: models.Criteria{Pagination: models.Pagination{Limit: 2000}},
+			want:     []fieldError{{"pagination.limit", models.LimitExceededCode}},
+		},
+		{
+			name: "several errors",
+			criteria: condition(
+				models.Condition{Field: "email", Operator: models.EqualsOperator, Value: "a"},
+				models.Condition{Field: "age", Operator: models.GreaterThan, Value: "5"},
+			),
+			want: []fieldError{
+				{"query.filters[0].logical", models.RequiredCode},
+				{"query.filters[0].conditions[0].field", models.UnknownFieldCode},
+				{"query.filters[0].conditions[1].value", models.TypeMismatchCode},
+			},
+		},
+		{
+			name:         "invalid super filter",
+			superFilters: []models.SuperFilter{{Operator: "~"}},
+			want: []fieldError{
+				{"super_filters[0].field", models.RequiredCode},
+				{"super_filters[0].operator", models.InvalidOperatorCode},
+				{"super_filters[0].value", models.RequiredCode},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := qt.ToMongo("clients", tt.criteria, tt.superFilters)
+			if !errors.Is(err, sentinels.ErrValidation) {
+				t.Fatalf("ToMongo() error = %v, want a validation error", err)
+			}
+			var fe *models.FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("ToMongo() error = %v, want a *models.FieldError", err)
+			}
+
+			got := models.FieldErrors(err)
+			if len(got) != len(tt.want) {
+				t.Fatalf("FieldErrors() = %v, want %v", got, tt.want)
+			}
+			for index, want := range tt.want {
+				if got[index].Path != want.path || got[index].Code != want.code {
+					t.Errorf("error[%d] = %s %s, want %s %s", index, got[index].Path, got[index].Code, want.path, want.code)
+				}
+			}
+		})
+	}
+}
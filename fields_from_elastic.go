@@ -0,0 +1,202 @@
+package searcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+// elasticFieldTypes are the Elasticsearch mapping types that can be searched with their FieldType.
+var elasticFieldTypes = map[string]models.FieldType{
+	"keyword":          models.String,
+	"constant_keyword": models.String,
+	"wildcard":         models.String,
+	"text":             models.String,
+	"date":             models.Date,
+	"date_nanos":       models.Date,
+	"long":             models.Number,
+	"integer":          models.Number,
+	"short":            models.Number,
+	"byte":             models.Number,
+	"double":           models.Number,
+	"float":            models.Number,
+	"half_float":       models.Number,
+	"scaled_float":     models.Number,
+	"unsigned_long":    models.Number,
+	"boolean":          models.Boolean,
+	"geo_point":        models.Geo,
+}
+
+// MappingOption configures FieldsFromElasticMapping.
+type MappingOption func(*mappingOptions)
+
+type mappingOptions struct {
+	allowed         []string
+	keywordSubField string
+}
+
+// WithAllowedFields limits the fields taken from the mapping to the given paths, the paths can be
+// patterns of path.Match (e.g. "address.*"). The paths without wildcards must exist in the mapping.
+func WithAllowedFields(fields ...string) MappingOption {
+	return func(o *mappingOptions) {
+		o.allowed = fields
+	}
+}
+
+// WithKeywordSubField sets the name of the keyword sub-field of the text fields, by default "raw"
+// (it must match the keyword suffix of the translator, check WithKeywordSuffix).
+func WithKeywordSubField(name string) MappingOption {
+	return func(o *mappingOptions) {
+		o.keywordSubField = name
+	}
+}
+
+// elasticProperty is a field of the properties of an Elasticsearch mapping.
+type elasticProperty struct {
+	Type       string                     `json:"type"`
+	Path       string                     `json:"path"`
+	Properties map[string]elasticProperty `json:"properties"`
+	Fields     map[string]elasticProperty `json:"fields"`
+}
+
+// FieldsFromElasticMapping derives the ValidFields of an entity from an Elasticsearch mapping, it accepts the
+// response of GET /<index>/_mapping (with one index), the "mappings" object or directly the object with the "properties".
+//
+// The fields are converted as follows:
+//
+// - keyword, date, numeric, boolean and geo_point fields are added with their FieldType.
+//
+// - text fields are added as analyzed String fields when they have a keyword sub-field (check WithKeywordSubField),
+// otherwise they are skipped because they cannot be filtered by their exact value.
+//
+// - object fields are walked and their fields are added with dotted paths (e.g. "address.district").
+//
+// - alias fields are added as Aliases of the field of their path.
+//
+// - nested fields and the rest of the types are skipped, the translators don't generate nested queries.
+func FieldsFromElasticMapping(entityName string, mapping []byte, opts ...MappingOption) (models.ValidFields, error) {
+	o := mappingOptions{keywordSubField: strings.TrimPrefix(DefaultKeywordSuffix, ".")}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(mapping, &doc); err != nil {
+		return models.ValidFields{}, fmt.Errorf("%w: invalid elastic mapping: %v", sentinels.ErrValidation, err)
+	}
+	properties, err := findMappingProperties(doc)
+	if err != nil {
+		return models.ValidFields{}, err
+	}
+
+	validFields := models.ValidFields{
+		EntityName: entityName,
+		Fields:     make(map[string]models.FieldMetaData),
+	}
+	aliases := make(map[string]string)
+	o.walkProperties(properties, "", validFields.Fields, aliases)
+
+	// The aliases are added sorted for get always the same field set from the same mapping
+	aliasNames := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		aliasNames = append(aliasNames, alias)
+	}
+	slices.Sort(aliasNames)
+	for _, alias := range aliasNames {
+		fmd, ok := validFields.Fields[aliases[alias]]
+		if ok && o.isAllowed(alias) {
+			fmd.Aliases = append(fmd.Aliases, alias)
+			validFields.Fields[aliases[alias]] = fmd
+		}
+	}
+
+	for _, allowed := range o.allowed {
+		_, isField := validFields.Fields[allowed]
+		_, isAlias := aliases[allowed]
+		if !strings.ContainsAny(allowed, "*?[") && !isField && !isAlias {
+			return models.ValidFields{}, fmt.Errorf("%w: allowed field %s is not a searchable field of the mapping", sentinels.ErrValidation, allowed)
+		}
+	}
+	return validFields, nil
+}
+
+// findMappingProperties looks for the properties object inside of the accepted mapping documents.
+func findMappingProperties(doc map[string]json.RawMessage) (map[string]elasticProperty, error) {
+	for depth := 0; depth < 3; depth++ {
+		if raw, ok := doc["properties"]; ok {
+			var properties map[string]elasticProperty
+			if err := json.Unmarshal(raw, &properties); err != nil {
+				return nil, fmt.Errorf("%w: invalid elastic mapping properties: %v", sentinels.ErrValidation, err)
+			}
+			return properties, nil
+		}
+
+		// The next level is the "mappings" object or the only key of the document (the index name or the legacy type name)
+		next, ok := doc["mappings"]
+		if !ok {
+			if len(doc) != 1 {
+				return nil, fmt.Errorf("%w: invalid elastic mapping: expected the mapping of one index", sentinels.ErrValidation)
+			}
+			for _, raw := range doc {
+				next = raw
+			}
+		}
+		doc = nil
+		if err := json.Unmarshal(next, &doc); err != nil {
+			return nil, fmt.Errorf("%w: invalid elastic mapping: %v", sentinels.ErrValidation, err)
+		}
+	}
+	return nil, fmt.Errorf("%w: invalid elastic mapping: properties not found", sentinels.ErrValidation)
+}
+
+// walkProperties adds the searchable properties to the fields map and collects the alias fields.
+func (o mappingOptions) walkProperties(properties map[string]elasticProperty, prefix string, fields map[string]models.FieldMetaData, aliases map[string]string) {
+	for name, property := range properties {
+		fieldPath := prefix + name
+		switch {
+		case property.Type == "nested":
+			continue
+		case property.Type == "alias":
+			aliases[fieldPath] = property.Path
+			continue
+		case property.Properties != nil && (property.Type == "" || property.Type == "object"):
+			o.walkProperties(property.Properties, fieldPath+".", fields, aliases)
+			continue
+		}
+
+		fieldType, ok := elasticFieldTypes[property.Type]
+		if !ok || !o.isAllowed(fieldPath) {
+			continue
+		}
+		fmd := models.FieldMetaData{
+			Field: models.Field(fieldPath),
+			Type:  fieldType,
+		}
+		if property.Type == "text" {
+			subField, ok := property.Fields[o.keywordSubField]
+			if !ok || subField.Type != "keyword" {
+				continue
+			}
+			fmd.IsAnalyzed = true
+		}
+		fields[fieldPath] = fmd
+	}
+}
+
+// isAllowed checks if the field path is in the allowed fields, all the fields are allowed when the list is empty.
+func (o mappingOptions) isAllowed(fieldPath string) bool {
+	if len(o.allowed) == 0 {
+		return true
+	}
+	for _, allowed := range o.allowed {
+		if matched, _ := path.Match(allowed, fieldPath); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package searcher_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+func TestFieldsFromElasticMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping string
+		opts    []searcher.MappingOption
+		want    map[string]models.FieldMetaData
+		wantErr bool
+	}{
+		{
+			name: "scalar types",
+			mapping: `{"properties": {
+				"code": {"type": "keyword"},
+				"created_at": {"type": "date"},
+				"amount": {"type": "scaled_float"},
+				"count": {"type": "long"},
+				"active": {"type": "boolean"},
+				"location": {"type": "geo_point"}
+			}}`,
+			want: map[string]models.FieldMetaData{
+				"code":       {Field: "code", Type: models.String},
+				"created_at": {Field: "created_at", Type: models.Date},
+				"amount":     {Field: "amount", Type: models.Number},
+				"count":      {Field: "count", Type: models.Number},
+				"active":     {Field: "active", Type: models.Boolean},
+				"location":   {Field: "location", Type: models.Geo},
+			},
+		},
+		{
+			name: "text with keyword sub-field",
+			mapping: `{"properties": {
+				"name": {"type": "text", "fields": {"raw": {"type": "keyword"}}},
+				"bio": {"type": "text"},
+				"title": {"type": "text", "fields": {"keyword": {"type": "keyword"}}}
+			}}`,
+			want: map[string]models.FieldMetaData{
+				"name": {Field: "name", Type: models.String, IsAnalyzed: true},
+			},
+		},
+		{
+			name: "custom keyword sub-field",
+			mapping: `{"properties": {
+				"name": {"type": "text", "fields": {"raw": {"type": "keyword"}}},
+				"title": {"type": "text", "fields": {"keyword": {"type": "keyword"}}}
+			}}`,
+			opts: []searcher.MappingOption{searcher.WithKeywordSubField("keyword")},
+			want: map[string]models.FieldMetaData{
+				"title": {Field: "title", Type: models.String, IsAnalyzed: true},
+			},
+		},
+		{
+			name: "object, nested and alias properties",
+			mapping: `{"properties": {
+				"address": {"properties": {"district": {"type": "keyword"}, "geo": {"type": "object", "properties": {"point": {"type": "geo_point"}}}}},
+				"items": {"type": "nested", "properties": {"sku": {"type": "keyword"}}},
+				"district": {"type": "alias", "path": "address.district"}
+			}}`,
+			want: map[string]models.FieldMetaData{
+				"address.district":  {Field: "address.district", Type: models.String, Aliases: []string{"district"}},
+				"address.geo.point": {Field: "address.geo.point", Type: models.Geo},
+			},
+		},
+		{
+			name: "unsupported types",
+			mapping: `{"properties": {
+				"shape": {"type": "geo_shape"},
+				"vector": {"type": "dense_vector"},
+				"payload": {"type": "binary"},
+				"code": {"type": "keyword"}
+			}}`,
+			want: map[string]models.FieldMetaData{
+				"code": {Field: "code", Type: models.String},
+			},
+		},
+		{
+			name:    "get mapping response",
+			mapping: `{"clients": {"mappings": {"properties": {"code": {"type": "keyword"}}}}}`,
+			want: map[string]models.FieldMetaData{
+				"code": {Field: "code", Type: models.String},
+			},
+		},
+		{
+			name:    "allowed fields",
+			mapping: `{"mappings": {"properties": {"code": {"type": "keyword"}, "secret": {"type": "keyword"}}}}`,
+			opts:    []searcher.MappingOption{searcher.WithAllowedFields("code")},
+			want: map[string]models.FieldMetaData{
+				"code": {Field: "code", Type: models.String},
+			},
+		},
+		{
+			name:    "allowed field not searchable",
+			mapping: `{"properties": {"shape": {"type": "geo_shape"}}}`,
+			opts:    []searcher.MappingOption{searcher.WithAllowedFields("shape")},
+			wantErr: true,
+		},
+		{
+			name:    "several indexes",
+			mapping: `{"a": {"mappings": {"properties": {}}}, "b": {"mappings": {"properties": {}}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			mapping: `{"properties": `,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vf, err := searcher.FieldsFromElasticMapping("clients", []byte(tt.mapping), tt.opts...)
+			if tt.wantErr {
+				if !errors.Is(err, sentinels.ErrValidation) {
+					t.Fatalf("FieldsFromElasticMapping() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(vf.Fields, tt.want) {
+				t.Errorf("fields = %v, want %v", vf.Fields, tt.want)
+			}
+		})
+	}
+}
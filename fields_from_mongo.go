@@ -0,0 +1,200 @@
+package searcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// mongoFieldTypes are the BSON types (bsonType) and the JSON types (type) of a $jsonSchema that can be searched with
+// their FieldType. The "timestamp" type is not included, it's the internal type of the replication and it's not
+// comparable with the dates (ISODate) produced by the Date fields.
+var mongoFieldTypes = map[string]models.FieldType{
+	"string":   models.String,
+	"date":     models.Date,
+	"int":      models.Number,
+	"long":     models.Number,
+	"double":   models.Number,
+	"decimal":  models.Number,
+	"number":   models.Number,
+	"integer":  models.Number,
+	"bool":     models.Boolean,
+	"boolean":  models.Boolean,
+	"objectId": models.ObjectID,
+}
+
+// jsonSchemaNode is a schema of a $jsonSchema document, bsonType and type can be a string or a list of strings.
+type jsonSchemaNode struct {
+	BSONType   interface{}               `bson:"bsonType"`
+	Type       interface{}               `bson:"type"`
+	Properties map[string]jsonSchemaNode `bson:"properties"`
+	Items      bson.RawValue             `bson:"items"`
+}
+
+// FieldsFromMongoJSONSchema derives the ValidFields of an entity from the $jsonSchema validator of a MongoDB collection.
+//
+// The schema can be the validator document ({"$jsonSchema": {...}}) or directly the $jsonSchema, as a bson.M, bson.D,
+// bson.Raw or any value that can be marshalled to BSON, or as a []byte or string with (Extended) JSON.
+//
+// The fields are converted as follows:
+//
+// - string, date, int, long, double, decimal, bool and objectId are added with their FieldType, the "null"
+// type is ignored so ["string", "null"] is a String field. The fields with more than one type and the fields of
+// other types (e.g. timestamp) are skipped, they are reported as an error when WithAllowedFields names them.
+//
+// - object fields with properties are walked and their fields are added with dotted paths (e.g. "address.district").
+//
+// - array fields are added with the type of their items, if the items are objects their fields are added with dotted
+// paths because MongoDB matches the conditions against the elements of the arrays. The fields of the arrays are
+// marked as MultiValued.
+//
+// The WithAllowedFields option limits the fields taken from the schema.
+func FieldsFromMongoJSONSchema(entityName string, schema interface{}, opts ...MappingOption) (models.ValidFields, error) {
+	var o mappingOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var doc bson.Raw
+	var err error
+	switch value := schema.(type) {
+	case []byte:
+		err = bson.UnmarshalExtJSON(value, false, &doc)
+	case string:
+		err = bson.UnmarshalExtJSON([]byte(value), false, &doc)
+	case bson.Raw:
+		doc = value
+	default:
+		doc, err = bson.Marshal(value)
+	}
+	if err != nil {
+		return models.ValidFields{}, fmt.Errorf("%w: invalid $jsonSchema: %v", sentinels.ErrValidation, err)
+	}
+
+	// The validator of a collection contains the schema inside of the $jsonSchema key
+	if raw, lookupErr := doc.LookupErr("$jsonSchema"); lookupErr == nil {
+		nested, ok := raw.DocumentOK()
+		if !ok {
+			return models.ValidFields{}, fmt.Errorf("%w: invalid $jsonSchema: must be a document", sentinels.ErrValidation)
+		}
+		doc = nested
+	}
+
+	var root jsonSchemaNode
+	if err := bson.Unmarshal(doc, &root); err != nil {
+		return models.ValidFields{}, fmt.Errorf("%w: invalid $jsonSchema: %v", sentinels.ErrValidation, err)
+	}
+	if root.Properties == nil {
+		return models.ValidFields{}, fmt.Errorf("%w: invalid $jsonSchema: properties not found", sentinels.ErrValidation)
+	}
+
+	validFields := models.ValidFields{
+		EntityName: entityName,
+		Fields:     make(map[string]models.FieldMetaData),
+	}
+	if err := o.walkSchemaProperties(root.Properties, "", false, validFields.Fields); err != nil {
+		return models.ValidFields{}, err
+	}
+
+	for _, allowed := range o.allowed {
+		if _, ok := validFields.Fields[allowed]; !ok && !strings.ContainsAny(allowed, "*?[") {
+			return models.ValidFields{}, fmt.Errorf("%w: allowed field %s is not a searchable field of the schema", sentinels.ErrValidation, allowed)
+		}
+	}
+	return validFields, nil
+}
+
+// walkSchemaProperties adds the searchable properties to the fields map, multiValued is true for the properties of
+// the objects inside of an array.
+func (o mappingOptions) walkSchemaProperties(properties map[string]jsonSchemaNode, prefix string, multiValued bool, fields map[string]models.FieldMetaData) error {
+	for name, node := range properties {
+		if err := o.addSchemaNode(node, prefix+name, multiValued, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSchemaNode adds the field of the node or walks it if it's an object or an array.
+func (o mappingOptions) addSchemaNode(node jsonSchemaNode, fieldPath string, multiValued bool, fields map[string]models.FieldMetaData) error {
+	schemaType, err := node.schemaType()
+	if err != nil {
+		return fmt.Errorf("%w: invalid $jsonSchema: %s: %v", sentinels.ErrValidation, fieldPath, err)
+	}
+
+	switch schemaType {
+	case "object":
+		return o.walkSchemaProperties(node.Properties, fieldPath+".", multiValued, fields)
+	case "array":
+		// A list of schemas in items is a tuple validation, the elements can have different types so it's skipped
+		if node.Items.Type != bson.TypeEmbeddedDocument {
+			return nil
+		}
+		var items jsonSchemaNode
+		if err := node.Items.Unmarshal(&items); err != nil {
+			return fmt.Errorf("%w: invalid $jsonSchema: %s.items: %v", sentinels.ErrValidation, fieldPath, err)
+		}
+		return o.addSchemaNode(items, fieldPath, true, fields)
+	}
+
+	fieldType, ok := mongoFieldTypes[schemaType]
+	if !ok || !o.isAllowed(fieldPath) {
+		return nil
+	}
+	fields[fieldPath] = models.FieldMetaData{
+		Field:       models.Field(fieldPath),
+		Type:        fieldType,
+		MultiValued: multiValued,
+	}
+	return nil
+}
+
+// schemaType returns the type of the node ignoring the "null" type, bsonType has priority over type.
+// A node with properties and without type is an object. An empty string is returned for the nodes with
+// more than one type or without type.
+func (node jsonSchemaNode) schemaType() (string, error) {
+	declared := node.BSONType
+	if declared == nil {
+		declared = node.Type
+	}
+
+	var types []string
+	switch value := declared.(type) {
+	case nil:
+		if node.Properties != nil {
+			return "object", nil
+		}
+		return "", nil
+	case string:
+		types = []string{value}
+	case bson.A:
+		for _, t := range value {
+			s, ok := t.(string)
+			if !ok {
+				return "", fmt.Errorf("invalid type: %v", t)
+			}
+			types = append(types, s)
+		}
+	default:
+		return "", fmt.Errorf("invalid type: %v", value)
+	}
+
+	schemaType := ""
+	for _, t := range types {
+		if t == "null" || t == schemaType {
+			continue
+		}
+		if schemaType == "" {
+			schemaType = t
+			continue
+		}
+		// The numeric types are the same FieldType so ["int", "double"] is a Number field
+		if mongoFieldTypes[t] == "" || mongoFieldTypes[t] != mongoFieldTypes[schemaType] {
+			return "", nil
+		}
+	}
+	return schemaType, nil
+}
@@ -0,0 +1,124 @@
+package searcher_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFieldsFromMongoJSONSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  interface{}
+		opts    []searcher.MappingOption
+		want    map[string]models.FieldMetaData
+		wantErr bool
+	}{
+		{
+			name: "scalar types",
+			schema: `{"$jsonSchema": {"bsonType": "object", "properties": {
+				"name": {"bsonType": "string"},
+				"created_at": {"bsonType": "date"},
+				"amount": {"bsonType": ["int", "double"]},
+				"active": {"type": "boolean"},
+				"owner_id": {"bsonType": "objectId"},
+				"deleted_at": {"bsonType": ["date", "null"]}
+			}}}`,
+			want: map[string]models.FieldMetaData{
+				"name":       {Field: "name", Type: models.String},
+				"created_at": {Field: "created_at", Type: models.Date},
+				"amount":     {Field: "amount", Type: models.Number},
+				"active":     {Field: "active", Type: models.Boolean},
+				"owner_id":   {Field: "owner_id", Type: models.ObjectID},
+				"deleted_at": {Field: "deleted_at", Type: models.Date},
+			},
+		},
+		{
+			name: "unsupported and mixed types are skipped",
+			schema: bson.M{"properties": bson.M{
+				"synced_at": bson.M{"bsonType": "timestamp"},
+				"payload":   bson.M{"bsonType": "binData"},
+				"mixed":     bson.M{"bsonType": bson.A{"string", "int"}},
+				"name":      bson.M{"bsonType": "string"},
+			}},
+			want: map[string]models.FieldMetaData{
+				"name": {Field: "name", Type: models.String},
+			},
+		},
+		{
+			name: "nested objects and arrays",
+			schema: bson.M{"properties": bson.M{
+				"address": bson.M{"bsonType": "object", "properties": bson.M{
+					"district": bson.M{"bsonType": "string"},
+				}},
+				"tags": bson.M{"bsonType": "array", "items": bson.M{"bsonType": "string"}},
+				"items": bson.M{"bsonType": "array", "items": bson.M{"properties": bson.M{
+					"sku": bson.M{"bsonType": "string"},
+				}}},
+				"tuple": bson.M{"bsonType": "array", "items": bson.A{bson.M{"bsonType": "string"}}},
+			}},
+			want: map[string]models.FieldMetaData{
+				"address.district": {Field: "address.district", Type: models.String},
+				"tags":             {Field: "tags", Type: models.String, MultiValued: true},
+				"items.sku":        {Field: "items.sku", Type: models.String, MultiValued: true},
+			},
+		},
+		{
+			name: "allowed fields",
+			schema: bson.M{"properties": bson.M{
+				"name":    bson.M{"bsonType": "string"},
+				"address": bson.M{"properties": bson.M{"district": bson.M{"bsonType": "string"}, "zip": bson.M{"bsonType": "string"}}},
+				"secret":  bson.M{"bsonType": "string"},
+			}},
+			opts: []searcher.MappingOption{searcher.WithAllowedFields("name", "address.*")},
+			want: map[string]models.FieldMetaData{
+				"name":             {Field: "name", Type: models.String},
+				"address.district": {Field: "address.district", Type: models.String},
+				"address.zip":      {Field: "address.zip", Type: models.String},
+			},
+		},
+		{
+			name:    "allowed timestamp field",
+			schema:  bson.M{"properties": bson.M{"synced_at": bson.M{"bsonType": "timestamp"}}},
+			opts:    []searcher.MappingOption{searcher.WithAllowedFields("synced_at")},
+			wantErr: true,
+		},
+		{
+			name:    "without properties",
+			schema:  bson.M{"bsonType": "object"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			schema:  `{"properties": `,
+			wantErr: true,
+		},
+		{
+			name:    "invalid type",
+			schema:  bson.M{"properties": bson.M{"name": bson.M{"bsonType": 1}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vf, err := searcher.FieldsFromMongoJSONSchema("clients", tt.schema, tt.opts...)
+			if tt.wantErr {
+				if !errors.Is(err, sentinels.ErrValidation) {
+					t.Fatalf("FieldsFromMongoJSONSchema() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(vf.Fields, tt.want) {
+				t.Errorf("fields = %v, want %v", vf.Fields, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package searcher
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// searcherTag is the struct tag read by FieldsFromStruct.
+const searcherTag = "searcher"
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	geoPointType = reflect.TypeOf(models.GeoPoint{})
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+)
+
+// StructOption configures FieldsFromStruct.
+type StructOption func(*structOptions)
+
+type structOptions struct {
+	nameTags []string
+}
+
+// WithNameTags sets the ordered list of struct tags used for get the name of the fields, by default "json" and
+// then "bson". The first tag present in the field decides its name like encoding/json, when it has no name
+// (e.g. `json:",omitempty"`) or none of the tags are present the Go field name is used.
+func WithNameTags(tags ...string) StructOption {
+	return func(o *structOptions) {
+		o.nameTags = tags
+	}
+}
+
+// FieldsFromStruct derives the ValidFields of an entity from the struct tags of v (a struct or a pointer to a struct).
+//
+// Only the fields with a `searcher` tag are included, the tag is a comma separated list of:
+//
+// - filter: the field can be used in the conditions.
+//
+// - sort: the field can be used in the sorts. When neither filter nor sort are set the field can be used in both.
+//
+// - type=<type>: the FieldType of the field (string, number, date, geo, boolean or object_id), by default it's inferred
+// from the Go type: time.Time is Date, the numerics are Number, bool is Boolean, models.GeoPoint is Geo,
+// primitive.ObjectID is ObjectID and string is String. The type of the byte slices (e.g. []byte) cannot be inferred.
+//
+// - analyzed: the field is analyzed in Elasticsearch (check FieldMetaData.IsAnalyzed), only for String fields.
+//
+// - case_insensitive: the equality ignores the case (check FieldMetaData.IsCaseInsensitive), only for String fields.
+//
+// - multi_valued: the field holds arrays (check FieldMetaData.MultiValued), it's set for the slices and arrays and
+// for the fields of the nested structs inside of them.
+//
+// - roles=<role>|<role>: only the callers with any of the roles can filter and sort by the field (check FieldMetaData.Roles).
+//
+// - "-": the field is ignored, for a struct field its nested fields are ignored too.
+//
+// The name of the field is taken from the json tag and then from the bson tag (check WithNameTags). The nested
+// structs (and slices of structs) are walked and their fields are added with dotted paths (e.g. "address.district"),
+// the embedded structs add their fields without prefix like encoding/json does.
+//
+// e.g.
+//
+//	type Client struct {
+//		Name      string    `json:"name" searcher:"filter,sort,analyzed"`
+//		CreatedAt time.Time `json:"created_at" searcher:"sort"`
+//		Address   Address   `json:"address"`
+//	}
+//
+//	validFields, err := searcher.FieldsFromStruct("clients", Client{})
+func FieldsFromStruct(entityName string, v any, opts ...StructOption) (models.ValidFields, error) {
+	o := structOptions{nameTags: []string{"json", "bson"}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return models.ValidFields{}, fmt.Errorf("%w: %T is not a struct", sentinels.ErrValidation, v)
+	}
+
+	validFields := models.ValidFields{
+		EntityName: entityName,
+		Fields:     make(map[string]models.FieldMetaData),
+	}
+	if err := o.walkStruct(t, "", false, map[reflect.Type]bool{}, validFields.Fields); err != nil {
+		return models.ValidFields{}, err
+	}
+	return validFields, nil
+}
+
+// walkStruct adds the tagged fields of the struct type to the fields map using the prefix for their names,
+// multiValued is true when the struct is inside of a slice or an array and visiting contains the struct types of
+// the current path for avoid infinite recursion.
+func (o structOptions) walkStruct(t reflect.Type, prefix string, multiValued bool, visiting map[reflect.Type]bool, fields map[string]models.FieldMetaData) error {
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+
+		name, skip := o.fieldName(sf)
+		tag, hasTag := sf.Tag.Lookup(searcherTag)
+		if skip || tag == "-" {
+			continue
+		}
+
+		ft, isList := indirect(sf.Type)
+		fieldMultiValued := multiValued || isList
+		if isNestedStruct(ft) {
+			nestedPrefix := prefix + name + "."
+			// The embedded structs without name promote their fields to the parent like encoding/json
+			if sf.Anonymous && !o.hasName(sf) {
+				nestedPrefix = prefix
+			}
+			if err := o.walkStruct(ft, nestedPrefix, fieldMultiValued, visiting, fields); err != nil {
+				return err
+			}
+			continue
+		}
+		if !hasTag || !sf.IsExported() {
+			continue
+		}
+
+		path := prefix + name
+		fmd, err := parseSearcherTag(path, tag, ft)
+		if err != nil {
+			return err
+		}
+		if fieldMultiValued {
+			fmd.MultiValued = true
+		}
+		if _, ok := fields[path]; ok {
+			return fmt.Errorf("%w: field %s is declared more than one time", sentinels.ErrValidation, path)
+		}
+		fields[path] = fmd
+	}
+	return nil
+}
+
+// fieldName returns the name of the struct field using the name tags, skip is true when the field is ignored ("-").
+func (o structOptions) fieldName(sf reflect.StructField) (name string, skip bool) {
+	name, skip = o.tagName(sf)
+	if name == "" {
+		name = sf.Name
+	}
+	return name, skip
+}
+
+// hasName checks if the name tags set a name for the struct field.
+func (o structOptions) hasName(sf reflect.StructField) bool {
+	name, _ := o.tagName(sf)
+	return name != ""
+}
+
+// tagName returns the name set by the first name tag present in the struct field, even if it's empty (e.g.
+// `json:",omitempty"` doesn't fall through to the bson tag), skip is true when the field is ignored ("-").
+func (o structOptions) tagName(sf reflect.StructField) (name string, skip bool) {
+	for _, tagName := range o.nameTags {
+		tag, ok := sf.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		if tag == "-" {
+			return "", true
+		}
+		name, _, _ = strings.Cut(tag, ",")
+		return name, false
+	}
+	return "", false
+}
+
+// parseSearcherTag builds the FieldMetaData of a field from its searcher tag.
+func parseSearcherTag(path string, tag string, t reflect.Type) (models.FieldMetaData, error) {
+	fmd := models.FieldMetaData{
+		Field: models.Field(path),
+		Type:  inferFieldType(t),
+	}
+
+	filter, sort := false, false
+	for _, option := range strings.Split(tag, ",") {
+		option = strings.TrimSpace(option)
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "":
+		case "filter":
+			filter = true
+		case "sort":
+			sort = true
+		case "analyzed":
+			fmd.IsAnalyzed = true
+		case "case_insensitive":
+			fmd.IsCaseInsensitive = true
+		case "multi_valued":
+			fmd.MultiValued = true
+		case "roles":
+			for _, role := range strings.Split(value, "|") {
+				if role = strings.TrimSpace(role); role != "" {
+					fmd.Roles = append(fmd.Roles, role)
+				}
+			}
+		case "type":
+			ft, err := models.NewFieldType(value)
+			if err != nil {
+				return fmd, fmt.Errorf("%w: field %s: %v", sentinels.ErrValidation, path, err)
+			}
+			fmd.Type = ft
+		default:
+			return fmd, fmt.Errorf("%w: field %s: unknown searcher tag option: %s", sentinels.ErrValidation, path, option)
+		}
+	}
+
+	if fmd.Type.Equals(models.Undefined) {
+		return fmd, fmt.Errorf("%w: field %s: cannot infer the type of %s, use the type= option", sentinels.ErrValidation, path, t)
+	}
+	// The same rule of the field sets loaded from files (check LoadFieldSetsJSON)
+	if (fmd.IsAnalyzed || fmd.IsCaseInsensitive) && !fmd.Type.Equals(models.String) {
+		return fmd, fmt.Errorf("%w: field %s: only the string fields can be analyzed or case insensitive", sentinels.ErrValidation, path)
+	}
+	// If neither filter nor sort are set the field can be used for both
+	if filter || sort {
+		fmd.NotFilterable = !filter
+		fmd.NotSortable = !sort
+	}
+	return fmd, nil
+}
+
+// inferFieldType returns the FieldType for a Go type, Undefined if it cannot be inferred.
+func inferFieldType(t reflect.Type) models.FieldType {
+	switch t {
+	case timeType:
+		return models.Date
+	case geoPointType:
+		return models.Geo
+	case objectIDType:
+		return models.ObjectID
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return models.String
+	case reflect.Bool:
+		return models.Boolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return models.Number
+	}
+	return models.Undefined
+}
+
+// indirect returns the type behind pointers and the element type of slices and arrays (the databases match the
+// conditions against the elements of the arrays), isList is true when a slice or an array was unwrapped. The
+// primitive.ObjectID and the byte slices and arrays are leaf values.
+func indirect(t reflect.Type) (elem reflect.Type, isList bool) {
+	for t != objectIDType && !isBytes(t) {
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array:
+			isList = true
+			t = t.Elem()
+		case reflect.Pointer:
+			t = t.Elem()
+		default:
+			return t, isList
+		}
+	}
+	return t, isList
+}
+
+// isBytes checks if the type is a slice or an array of bytes, they are stored as binary data (or as a base64
+// string) instead of an array of numbers so their type cannot be inferred.
+func isBytes(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() == reflect.Uint8
+}
+
+// isNestedStruct checks if the type is a struct that must be walked, the time.Time and the models.GeoPoint are leaf values.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && t != geoPointType
+}
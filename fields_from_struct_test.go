@@ -0,0 +1,170 @@
+package searcher_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+func TestFieldsFromStructByteSlices(t *testing.T) {
+	tests := []struct {
+		name     string
+		v        any
+		wantType models.FieldType
+		wantErr  bool
+	}{
+		{
+			name: "byte slice without type",
+			v: struct {
+				Data []byte `searcher:"filter"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "byte array without type",
+			v: struct {
+				Data [16]byte `searcher:"filter"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "byte slice with type",
+			v: struct {
+				Data []byte `searcher:"filter,type=string"`
+			}{},
+			wantType: models.String,
+		},
+		{
+			name: "slice of numbers",
+			v: struct {
+				Data []int `searcher:"filter"`
+			}{},
+			wantType: models.Number,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vf, err := searcher.FieldsFromStruct("entity", tt.v)
+			if tt.wantErr {
+				if !errors.Is(err, sentinels.ErrValidation) {
+					t.Fatalf("FieldsFromStruct() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := vf.Fields["Data"].Type; got != tt.wantType {
+				t.Errorf("type = %s, want %s", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestFieldsFromStructMultiValued(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku" searcher:"filter"`
+	}
+	type order struct {
+		Status string   `json:"status" searcher:"filter"`
+		Tags   []string `json:"tags" searcher:"filter"`
+		Items  []item   `json:"items"`
+		Notes  *string  `json:"notes" searcher:"filter,multi_valued"`
+	}
+
+	vf, err := searcher.FieldsFromStruct("orders", order{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"status": false, "tags": true, "items.sku": true, "notes": true}
+	for field, multiValued := range want {
+		if got := vf.Fields[field].MultiValued; got != multiValued {
+			t.Errorf("%s.MultiValued = %v, want %v", field, got, multiValued)
+		}
+	}
+}
+
+func TestFieldsFromStructStringOnlyOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		wantErr bool
+	}{
+		{
+			name: "analyzed string",
+			v: struct {
+				Name string `searcher:"filter,analyzed,case_insensitive"`
+			}{},
+		},
+		{
+			name: "analyzed number",
+			v: struct {
+				Age int `searcher:"filter,analyzed"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "case insensitive date",
+			v: struct {
+				CreatedAt time.Time `searcher:"filter,case_insensitive"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "case insensitive with explicit type",
+			v: struct {
+				Code []byte `searcher:"filter,type=object_id,case_insensitive"`
+			}{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := searcher.FieldsFromStruct("entity", tt.v)
+			if tt.wantErr && !errors.Is(err, sentinels.ErrValidation) {
+				t.Fatalf("FieldsFromStruct() error = %v, want a validation error", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestFieldsFromStructNames(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `json:"created_by" searcher:"filter"`
+	}
+	type Meta struct {
+		Source string `json:"source" searcher:"filter"`
+	}
+	type entity struct {
+		Audit   `json:",omitempty" bson:"audit"`
+		Meta    `json:"meta"`
+		Name    string `json:"name" bson:"full_name" searcher:"filter"`
+		Email   string `json:",omitempty" bson:"email" searcher:"filter"`
+		Phone   string `bson:"phone" searcher:"filter"`
+		Secret  string `json:"-" searcher:"filter"`
+		Dash    string `json:"-," searcher:"filter"`
+		Country string `searcher:"filter"`
+	}
+
+	vf, err := searcher.FieldsFromStruct("entity", entity{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for field := range vf.Fields {
+		got = append(got, field)
+	}
+	slices.Sort(got)
+	want := []string{"-", "Country", "Email", "created_by", "meta.source", "name", "phone"}
+	if !slices.Equal(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,432 @@
+package searcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+	"gopkg.in/yaml.v3"
+)
+
+// fieldSetsDocument is the format of the configuration files of the valid field sets.
+//
+//	entities:
+//	  - entity: clients
+//	    limits:
+//	      maximum_limit: 100
+//	    fields:
+//	      - name: name
+//	        type: string
+//	        analyzed: true
+//	        aliases: [full_name]
+//	        operators: ["=", "!="]
+//	      - name: created_at
+//	        type: date
+//	        filterable: false
+type fieldSetsDocument struct {
+	Entities []fieldSetSpec `json:"entities" yaml:"entities"`
+}
+
+type fieldSetSpec struct {
+	Entity     string          `json:"entity" yaml:"entity"`
+	Limits     *limitsSpec     `json:"limits,omitempty" yaml:"limits,omitempty"`
+	Complexity *complexitySpec `json:"complexity,omitempty" yaml:"complexity,omitempty"`
+	Fields     []fieldSpec     `json:"fields" yaml:"fields"`
+	Indexes    []indexSpec     `json:"indexes,omitempty" yaml:"indexes,omitempty"`
+}
+
+type limitsSpec struct {
+	DefaultLimit           uint `json:"default_limit,omitempty" yaml:"default_limit,omitempty"`
+	MaximumLimit           uint `json:"maximum_limit,omitempty" yaml:"maximum_limit,omitempty"`
+	MaximumLimitOffsetSize uint `json:"maximum_limit_offset_size,omitempty" yaml:"maximum_limit_offset_size,omitempty"`
+}
+
+type complexitySpec struct {
+	MaxFilters             int `json:"max_filters,omitempty" yaml:"max_filters,omitempty"`
+	MaxConditionsPerFilter int `json:"max_conditions_per_filter,omitempty" yaml:"max_conditions_per_filter,omitempty"`
+	MaxConditions          int `json:"max_conditions,omitempty" yaml:"max_conditions,omitempty"`
+	MaxDepth               int `json:"max_depth,omitempty" yaml:"max_depth,omitempty"`
+	MaxListSize            int `json:"max_list_size,omitempty" yaml:"max_list_size,omitempty"`
+	MaxSorts               int `json:"max_sorts,omitempty" yaml:"max_sorts,omitempty"`
+	MaxCost                int `json:"max_cost,omitempty" yaml:"max_cost,omitempty"`
+}
+
+type indexSpec struct {
+	Name string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Keys []string `json:"keys" yaml:"keys"`
+}
+
+type fieldSpec struct {
+	Name            string   `json:"name" yaml:"name"`
+	Type            string   `json:"type" yaml:"type"`
+	Analyzed        bool     `json:"analyzed,omitempty" yaml:"analyzed,omitempty"`
+	CaseInsensitive bool     `json:"case_insensitive,omitempty" yaml:"case_insensitive,omitempty"`
+	MultiValued     bool     `json:"multi_valued,omitempty" yaml:"multi_valued,omitempty"`
+	Filterable      *bool    `json:"filterable,omitempty" yaml:"filterable,omitempty"`
+	Sortable        *bool    `json:"sortable,omitempty" yaml:"sortable,omitempty"`
+	Indexed         *bool    `json:"indexed,omitempty" yaml:"indexed,omitempty"`
+	Aliases         []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Operators       []string `json:"operators,omitempty" yaml:"operators,omitempty"`
+	Roles           []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+}
+
+// LoadFieldSetsJSON reads the valid field sets from a JSON document (check LoadFieldSetsYAML for the format),
+// the unknown keys are rejected.
+func LoadFieldSetsJSON(r io.Reader) ([]models.ValidFields, error) {
+	var doc fieldSetsDocument
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: invalid field sets JSON: %v", sentinels.ErrValidation, err)
+	}
+	// The content after the document is rejected as the invalid content of the document
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: invalid field sets JSON: unexpected content after the document", sentinels.ErrValidation)
+	}
+	return doc.toValidFields()
+}
+
+// LoadFieldSetsYAML reads the valid field sets from a YAML document, the unknown keys are rejected.
+//
+//	entities:
+//	  - entity: clients
+//	    limits:
+//	      maximum_limit: 100
+//	    complexity:                # max_filters, max_conditions_per_filter, max_conditions, max_depth,
+//	      max_conditions: 20       # max_list_size, max_sorts and max_cost (check models.ComplexityLimits)
+//	    fields:
+//	      - name: name
+//	        type: string           # string, number, date, geo, boolean or object_id
+//	        analyzed: true         # FieldMetaData.IsAnalyzed
+//	        case_insensitive: true # FieldMetaData.IsCaseInsensitive
+//	        aliases: [full_name]
+//	        operators: ["=", "!="] # all the operators when empty
+//	      - name: created_at
+//	        type: date
+//	        filterable: false      # by default the fields are filterable and sortable
+//	        indexed: false         # by default the fields are indexed (check FieldMetaData.NotIndexed)
+//	        roles: [admin]         # only the admins can filter and sort by the field (check FieldMetaData.Roles)
+//	      - name: tags
+//	        type: string
+//	        multi_valued: true     # the field holds arrays (check FieldMetaData.MultiValued)
+//	    indexes:                   # the compound indexes of the entity (check ValidFields.Indexes)
+//	      - name: name_created_at
+//	        keys: [name, -created_at] # the "-" prefix is the desc order
+func LoadFieldSetsYAML(r io.Reader) ([]models.ValidFields, error) {
+	var doc fieldSetsDocument
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: invalid field sets YAML: empty document", sentinels.ErrValidation)
+		}
+		return nil, fmt.Errorf("%w: invalid field sets YAML: %v", sentinels.ErrValidation, err)
+	}
+	// A stream with more than one document is rejected, the next documents would be ignored
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: invalid field sets YAML: unexpected content after the document", sentinels.ErrValidation)
+	}
+	return doc.toValidFields()
+}
+
+// LoadFieldSetsFS reads the valid field sets of all the files of fsys matching the glob patterns, the format is
+// chosen by the extension of the file (.json, .yaml or .yml). An entity cannot be declared in more than one file.
+func LoadFieldSetsFS(fsys fs.FS, patterns ...string) ([]models.ValidFields, error) {
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	slices.Sort(files)
+
+	var validFieldsSets []models.ValidFields
+	entities := make(map[string]string)
+	for _, file := range files {
+		sets, err := loadFieldSetsFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, vf := range sets {
+			if previous, ok := entities[vf.EntityName]; ok {
+				return nil, fmt.Errorf("%s: %w: entity %s is already declared in %s", file, sentinels.ErrValidation, vf.EntityName, previous)
+			}
+			entities[vf.EntityName] = file
+		}
+		validFieldsSets = append(validFieldsSets, sets...)
+	}
+	return validFieldsSets, nil
+}
+
+// LoadValidFieldsSetsFS reads the valid field sets from the files of fsys (check LoadFieldSetsFS) and registers
+// them replacing the previous sets of the same entities, so it can be called again for reload the configuration.
+// If any of the files is invalid none of the sets are registered.
+func (ca *QueryTranslator) LoadValidFieldsSetsFS(fsys fs.FS, patterns ...string) error {
+	sets, err := LoadFieldSetsFS(fsys, patterns...)
+	if err != nil {
+		return err
+	}
+	for _, vf := range sets {
+		if err := ca.ReplaceValidFieldsSet(vf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportFieldSetsJSON writes the valid field sets in the format read by LoadFieldSetsJSON, useful for document
+// the fields that can be searched.
+func ExportFieldSetsJSON(w io.Writer, sets ...models.ValidFields) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(newFieldSetsDocument(sets))
+}
+
+// ExportFieldSetsYAML writes the valid field sets in the format read by LoadFieldSetsYAML.
+func ExportFieldSetsYAML(w io.Writer, sets ...models.ValidFields) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(newFieldSetsDocument(sets)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// loadFieldSetsFile reads a file of fsys with the decoder of its extension.
+func loadFieldSetsFile(fsys fs.FS, file string) ([]models.ValidFields, error) {
+	content, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(path.Ext(file)) {
+	case ".json":
+		return LoadFieldSetsJSON(bytes.NewReader(content))
+	case ".yaml", ".yml":
+		return LoadFieldSetsYAML(bytes.NewReader(content))
+	}
+	return nil, fmt.Errorf("%w: unsupported field sets file extension (available:.json,.yaml,.yml)", sentinels.ErrValidation)
+}
+
+// toValidFields validates the document and converts it to the valid field sets.
+func (doc fieldSetsDocument) toValidFields() ([]models.ValidFields, error) {
+	if len(doc.Entities) == 0 {
+		return nil, fmt.Errorf("%w: %w", sentinels.ErrValidation, models.NewFieldError("entities", models.RequiredCode, nil, "at least one entity must be specified"))
+	}
+
+	var validationErrors models.ValidationErrors
+	sets := make([]models.ValidFields, 0, len(doc.Entities))
+	entities := make(map[string]bool)
+	for index, spec := range doc.Entities {
+		vf, errs := spec.toValidFields()
+		if spec.Entity != "" && entities[spec.Entity] {
+			errs = append(errs, models.NewFieldError("entity", models.InvalidValueCode, spec.Entity, fmt.Sprintf("%s is declared more than one time", spec.Entity)))
+		}
+		if len(errs) > 0 {
+			validationErrors = append(validationErrors, models.WithPathPrefix(fmt.Sprintf("entities[%v]", index), errs).(models.ValidationErrors)...)
+		}
+		entities[spec.Entity] = true
+		sets = append(sets, vf)
+	}
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("%w: %w", sentinels.ErrValidation, validationErrors)
+	}
+	return sets, nil
+}
+
+// toValidFields validates the field set and converts it, the paths of the errors are relative to the field set.
+func (spec fieldSetSpec) toValidFields() (models.ValidFields, models.ValidationErrors) {
+	var errs models.ValidationErrors
+	if spec.Entity == "" {
+		errs = append(errs, models.NewFieldError("entity", models.RequiredCode, nil, "cannot be empty"))
+	}
+	if len(spec.Fields) == 0 {
+		errs = append(errs, models.NewFieldError("fields", models.RequiredCode, nil, "at least one field must be specified"))
+	}
+
+	vf := models.ValidFields{
+		EntityName: spec.Entity,
+		Fields:     make(map[string]models.FieldMetaData, len(spec.Fields)),
+	}
+	if spec.Limits != nil {
+		vf.Limits = models.PaginationLimits{
+			DefaultLimit:           spec.Limits.DefaultLimit,
+			MaximumLimit:           spec.Limits.MaximumLimit,
+			MaximumLimitOffsetSize: spec.Limits.MaximumLimitOffsetSize,
+		}
+		if vf.Limits.MaximumLimit != 0 && vf.Limits.DefaultLimit > vf.Limits.MaximumLimit {
+			errs = append(errs, models.NewFieldError("limits.default_limit", models.InvalidValueCode, vf.Limits.DefaultLimit, "cannot be greater than maximum_limit"))
+		}
+	}
+	if spec.Complexity != nil {
+		vf.Complexity = models.ComplexityLimits(*spec.Complexity)
+		if vf.Complexity.MaxFilters < 0 || vf.Complexity.MaxConditionsPerFilter < 0 || vf.Complexity.MaxConditions < 0 ||
+			vf.Complexity.MaxDepth < 0 || vf.Complexity.MaxListSize < 0 || vf.Complexity.MaxSorts < 0 || vf.Complexity.MaxCost < 0 {
+			errs = append(errs, models.NewFieldError("complexity", models.InvalidValueCode, nil, "the limits cannot be negative"))
+		}
+	}
+
+	// names contains the names and aliases already used for detect collisions
+	names := make(map[string]string)
+	for index, field := range spec.Fields {
+		prefix := fmt.Sprintf("fields[%v]", index)
+		fmd, fieldErrs := field.toFieldMetaData()
+		if len(fieldErrs) > 0 {
+			errs = append(errs, models.WithPathPrefix(prefix, fieldErrs).(models.ValidationErrors)...)
+		}
+		for i, name := range append([]string{field.Name}, field.Aliases...) {
+			if name == "" {
+				continue
+			}
+			key := "name"
+			if i > 0 {
+				key = fmt.Sprintf("aliases[%v]", i-1)
+			}
+			if previous, ok := names[name]; ok {
+				errs = append(errs, models.NewFieldError(prefix+"."+key, models.InvalidValueCode, name, fmt.Sprintf("%s is already used by %s", name, previous)))
+				continue
+			}
+			names[name] = field.Name
+		}
+		vf.Fields[field.Name] = fmd
+	}
+
+	for index, is := range spec.Indexes {
+		prefix := fmt.Sprintf("indexes[%v]", index)
+		if len(is.Keys) == 0 {
+			errs = append(errs, models.NewFieldError(prefix+".keys", models.RequiredCode, nil, "at least one key must be specified"))
+			continue
+		}
+		i := models.Index{Name: is.Name, Keys: make([]models.IndexKey, 0, len(is.Keys))}
+		for keyIndex, key := range is.Keys {
+			indexKey := models.IndexKey{Field: strings.TrimPrefix(key, "-"), Order: models.ASCOrder}
+			if strings.HasPrefix(key, "-") {
+				indexKey.Order = models.DESCOrder
+			}
+			if _, ok := vf.Fields[indexKey.Field]; !ok {
+				errs = append(errs, models.NewFieldError(fmt.Sprintf("%s.keys[%v]", prefix, keyIndex), models.UnknownFieldCode, key, fmt.Sprintf("%s is not a field of the entity", indexKey.Field)))
+			}
+			i.Keys = append(i.Keys, indexKey)
+		}
+		vf.Indexes = append(vf.Indexes, i)
+	}
+	return vf, errs
+}
+
+// toFieldMetaData validates the field and converts it, the paths of the errors are relative to the field.
+func (field fieldSpec) toFieldMetaData() (models.FieldMetaData, models.ValidationErrors) {
+	var errs models.ValidationErrors
+	fmd := models.FieldMetaData{
+		Field:             models.Field(field.Name),
+		Type:              models.FieldType(field.Type),
+		IsAnalyzed:        field.Analyzed,
+		IsCaseInsensitive: field.CaseInsensitive,
+		MultiValued:       field.MultiValued,
+		NotFilterable:     field.Filterable != nil && !*field.Filterable,
+		NotSortable:       field.Sortable != nil && !*field.Sortable,
+		NotIndexed:        field.Indexed != nil && !*field.Indexed,
+		Aliases:           field.Aliases,
+		Roles:             field.Roles,
+	}
+
+	if err := fmd.Field.Validate(); err != nil {
+		errs = append(errs, models.NewFieldError("name", models.InvalidValueCode, field.Name, err.Error()))
+	}
+	if err := fmd.Type.Validate(); err != nil {
+		errs = append(errs, models.NewFieldError("type", models.InvalidValueCode, field.Type, err.Error()))
+	}
+	if (field.Analyzed || field.CaseInsensitive) && !fmd.Type.Equals(models.String) {
+		errs = append(errs, models.NewFieldError("type", models.InvalidValueCode, field.Type, "only the string fields can be analyzed or case insensitive"))
+	}
+	for index, alias := range field.Aliases {
+		if alias == "" {
+			errs = append(errs, models.NewFieldError(fmt.Sprintf("aliases[%v]", index), models.RequiredCode, nil, "cannot be empty"))
+		}
+	}
+	for index, role := range field.Roles {
+		if role == "" {
+			errs = append(errs, models.NewFieldError(fmt.Sprintf("roles[%v]", index), models.RequiredCode, nil, "cannot be empty"))
+		}
+	}
+	for index, o := range field.Operators {
+		operator, err := models.NewOperator(o)
+		if err != nil {
+			errs = append(errs, models.NewFieldError(fmt.Sprintf("operators[%v]", index), models.InvalidOperatorCode, o, err.Error()))
+			continue
+		}
+		if operator.IsGeo() != fmd.Type.Equals(models.Geo) {
+			errs = append(errs, models.NewFieldError(fmt.Sprintf("operators[%v]", index), models.InvalidOperatorCode, o, fmt.Sprintf("%s cannot be used with a %s field", operator, fmd.Type)))
+			continue
+		}
+		fmd.Operators = append(fmd.Operators, operator)
+	}
+	return fmd, errs
+}
+
+// newFieldSetsDocument converts the valid field sets to the configuration format sorting the fields by name.
+func newFieldSetsDocument(sets []models.ValidFields) fieldSetsDocument {
+	doc := fieldSetsDocument{Entities: make([]fieldSetSpec, 0, len(sets))}
+	for _, vf := range sets {
+		spec := fieldSetSpec{Entity: vf.EntityName, Fields: make([]fieldSpec, 0, len(vf.Fields))}
+		if vf.Limits != (models.PaginationLimits{}) {
+			spec.Limits = &limitsSpec{
+				DefaultLimit:           vf.Limits.DefaultLimit,
+				MaximumLimit:           vf.Limits.MaximumLimit,
+				MaximumLimitOffsetSize: vf.Limits.MaximumLimitOffsetSize,
+			}
+		}
+		if vf.Complexity != (models.ComplexityLimits{}) {
+			complexity := complexitySpec(vf.Complexity)
+			spec.Complexity = &complexity
+		}
+		for name, fmd := range vf.Fields {
+			field := fieldSpec{
+				Name:            name,
+				Type:            fmd.Type.String(),
+				Analyzed:        fmd.IsAnalyzed,
+				CaseInsensitive: fmd.IsCaseInsensitive,
+				MultiValued:     fmd.MultiValued,
+				Aliases:         fmd.Aliases,
+				Roles:           fmd.Roles,
+			}
+			if fmd.NotFilterable {
+				field.Filterable = new(bool)
+			}
+			if fmd.NotSortable {
+				field.Sortable = new(bool)
+			}
+			if fmd.NotIndexed {
+				field.Indexed = new(bool)
+			}
+			for _, operator := range fmd.Operators {
+				field.Operators = append(field.Operators, operator.String())
+			}
+			spec.Fields = append(spec.Fields, field)
+		}
+		slices.SortFunc(spec.Fields, func(a, b fieldSpec) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		for _, index := range vf.Indexes {
+			is := indexSpec{Name: index.Name, Keys: make([]string, 0, len(index.Keys))}
+			for _, key := range index.Keys {
+				if key.Order.Equals(models.DESCOrder) {
+					is.Keys = append(is.Keys, "-"+key.Field)
+					continue
+				}
+				is.Keys = append(is.Keys, key.Field)
+			}
+			spec.Indexes = append(spec.Indexes, is)
+		}
+		doc.Entities = append(doc.Entities, spec)
+	}
+	return doc
+}
@@ -0,0 +1,238 @@
+package searcher_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+// loaderFieldSet is the field set declared by the documents of the loader tests.
+var loaderFieldSet = models.ValidFields{
+	EntityName: "clients",
+	Limits:     models.PaginationLimits{DefaultLimit: 10, MaximumLimit: 100},
+	Complexity: models.ComplexityLimits{MaxConditions: 20, MaxDepth: 2},
+	Fields: map[string]models.FieldMetaData{
+		"name": {
+			Field:             "name",
+			Type:              models.String,
+			IsAnalyzed:        true,
+			IsCaseInsensitive: true,
+			Aliases:           []string{"full_name"},
+			Operators:         []models.Operator{models.EqualsOperator, models.NotEqualsOperator},
+		},
+		"created_at": {Field: "created_at", Type: models.Date, NotFilterable: true, NotIndexed: true, Roles: []string{"admin"}},
+		"tags":       {Field: "tags", Type: models.String, MultiValued: true},
+	},
+	Indexes: []models.Index{{Name: "name_created_at", Keys: []models.IndexKey{
+		{Field: "name", Order: models.ASCOrder},
+		{Field: "created_at", Order: models.DESCOrder},
+	}}},
+}
+
+const loaderYAML = `
+entities:
+  - entity: clients
+    limits:
+      default_limit: 10
+      maximum_limit: 100
+    complexity:
+      max_conditions: 20
+      max_depth: 2
+    fields:
+      - name: name
+        type: string
+        analyzed: true
+        case_insensitive: true
+        aliases: [full_name]
+        operators: ["=", "!="]
+      - name: created_at
+        type: date
+        filterable: false
+        indexed: false
+        roles: [admin]
+      - name: tags
+        type: string
+        multi_valued: true
+    indexes:
+      - name: name_created_at
+        keys: [name, -created_at]
+`
+
+const loaderJSON = `{"entities": [{
+	"entity": "clients",
+	"limits": {"default_limit": 10, "maximum_limit": 100},
+	"complexity": {"max_conditions": 20, "max_depth": 2},
+	"fields": [
+		{"name": "name", "type": "string", "analyzed": true, "case_insensitive": true, "aliases": ["full_name"], "operators": ["=", "!="]},
+		{"name": "created_at", "type": "date", "filterable": false, "indexed": false, "roles": ["admin"]},
+		{"name": "tags", "type": "string", "multi_valued": true}
+	],
+	"indexes": [{"name": "name_created_at", "keys": ["name", "-created_at"]}]
+}]}`
+
+func TestLoadFieldSets(t *testing.T) {
+	tests := []struct {
+		name string
+		load func() ([]models.ValidFields, error)
+	}{
+		{name: "yaml", load: func() ([]models.ValidFields, error) { return searcher.LoadFieldSetsYAML(strings.NewReader(loaderYAML)) }},
+		{name: "json", load: func() ([]models.ValidFields, error) { return searcher.LoadFieldSetsJSON(strings.NewReader(loaderJSON)) }},
+		{name: "fs", load: func() ([]models.ValidFields, error) {
+			return searcher.LoadFieldSetsFS(fstest.MapFS{"fields/clients.yml": {Data: []byte(loaderYAML)}}, "fields/*.yml")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sets, err := tt.load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []models.ValidFields{loaderFieldSet}; !reflect.DeepEqual(sets, want) {
+				t.Errorf("sets = %+v, want %+v", sets, want)
+			}
+		})
+	}
+}
+
+func TestExportFieldSetsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		export func(buf *bytes.Buffer) error
+		load   func(buf *bytes.Buffer) ([]models.ValidFields, error)
+	}{
+		{
+			name:   "yaml",
+			export: func(buf *bytes.Buffer) error { return searcher.ExportFieldSetsYAML(buf, loaderFieldSet) },
+			load:   func(buf *bytes.Buffer) ([]models.ValidFields, error) { return searcher.LoadFieldSetsYAML(buf) },
+		},
+		{
+			name:   "json",
+			export: func(buf *bytes.Buffer) error { return searcher.ExportFieldSetsJSON(buf, loaderFieldSet) },
+			load:   func(buf *bytes.Buffer) ([]models.ValidFields, error) { return searcher.LoadFieldSetsJSON(buf) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.export(&buf); err != nil {
+				t.Fatal(err)
+			}
+			sets, err := tt.load(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []models.ValidFields{loaderFieldSet}; !reflect.DeepEqual(sets, want) {
+				t.Errorf("sets = %+v, want %+v", sets, want)
+			}
+		})
+	}
+}
+
+func TestLoadFieldSetsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		load      func() ([]models.ValidFields, error)
+		wantPaths []string
+	}{
+		{
+			name: "json trailing content",
+			load: func() ([]models.ValidFields, error) {
+				return searcher.LoadFieldSetsJSON(strings.NewReader(loaderJSON + `{"entities": []}`))
+			},
+		},
+		{
+			name: "json unknown key",
+			load: func() ([]models.ValidFields, error) {
+				return searcher.LoadFieldSetsJSON(strings.NewReader(`{"entities": [{"entity": "clients", "unknown": 1}]}`))
+			},
+		},
+		{
+			name: "yaml several documents",
+			load: func() ([]models.ValidFields, error) {
+				return searcher.LoadFieldSetsYAML(strings.NewReader(loaderYAML + "---\nentities: []\n"))
+			},
+		},
+		{
+			name: "yaml without entities",
+			load: func() ([]models.ValidFields, error) {
+				return searcher.LoadFieldSetsYAML(strings.NewReader("entities: []\n"))
+			},
+			wantPaths: []string{"entities"},
+		},
+		{
+			name: "invalid fields",
+			load: func() ([]models.ValidFields, error) {
+				return searcher.LoadFieldSetsYAML(strings.NewReader(`
+entities:
+  - entity: clients
+    fields:
+      - name: amount
+        type: money
+      - name: active
+        type: boolean
+        case_insensitive: true
+      - name: code
+        type: string
+        aliases: [amount]
+    indexes:
+      - keys: [missing]
+  - entity: clients
+    fields:
+      - name: name
+        type: string
+`))
+			},
+			wantPaths: []string{
+				"entities[0].fields[0].type",
+				"entities[0].fields[1].type",
+				"entities[0].fields[2].aliases[0]",
+				"entities[0].indexes[0].keys[0]",
+				"entities[1].entity",
+			},
+		},
+		{
+			name: "fs entity declared in two files",
+			load: func() ([]models.ValidFields, error) {
+				return searcher.LoadFieldSetsFS(fstest.MapFS{
+					"a.yaml": {Data: []byte(loaderYAML)},
+					"b.json": {Data: []byte(loaderJSON)},
+				}, "*")
+			},
+		},
+		{
+			name: "fs unsupported extension",
+			load: func() ([]models.ValidFields, error) {
+				return searcher.LoadFieldSetsFS(fstest.MapFS{"clients.toml": {Data: []byte("")}}, "*")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.load()
+			if !errors.Is(err, sentinels.ErrValidation) {
+				t.Fatalf("error = %v, want a validation error", err)
+			}
+			if tt.wantPaths == nil {
+				return
+			}
+			var fieldError *models.FieldError
+			if !errors.As(err, &fieldError) {
+				t.Fatalf("error = %v, want a FieldError", err)
+			}
+			var paths []string
+			for _, fe := range models.FieldErrors(err) {
+				paths = append(paths, fe.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("paths = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
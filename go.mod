@@ -3,3 +3,5 @@ module github.com/solrac97gr/searcher
 go 1.22.0
 
 require go.mongodb.org/mongo-driver v1.14.0
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,151 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+// DefaultLocale is the locale used when the requested locale has no message for an error code.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var embeddedLocales embed.FS
+
+// Messages are the message templates of a locale by error code, the templates can use the placeholders
+// {value} (the FieldError.Value), {path} (the FieldError.Path) and {<param>} (the FieldError.Params).
+type Messages map[models.ErrorCode]string
+
+// Catalog contains the messages of the validation errors by locale.
+type Catalog struct {
+	messages       map[string]Messages
+	fallbackLocale string
+}
+
+// CatalogOption configures a Catalog.
+type CatalogOption func(*Catalog)
+
+// WithMessages adds the messages of a locale (e.g. "fr" or "es-PE") to the catalog, the messages replace the
+// embedded messages of the locale with the same code so they can be used for customize the texts.
+func WithMessages(locale string, messages Messages) CatalogOption {
+	return func(c *Catalog) {
+		locale = normalizeLocale(locale)
+		if c.messages[locale] == nil {
+			c.messages[locale] = make(Messages, len(messages))
+		}
+		for code, message := range messages {
+			c.messages[locale][code] = message
+		}
+	}
+}
+
+// WithFallbackLocale sets the locale used when the requested locale has no message for an error code,
+// by default DefaultLocale.
+func WithFallbackLocale(locale string) CatalogOption {
+	return func(c *Catalog) {
+		c.fallbackLocale = normalizeLocale(locale)
+	}
+}
+
+// NewCatalog creates a Catalog with the embedded messages in English ("en"), Spanish ("es") and Portuguese ("pt").
+func NewCatalog(opts ...CatalogOption) (*Catalog, error) {
+	c := &Catalog{
+		messages:       make(map[string]Messages),
+		fallbackLocale: DefaultLocale,
+	}
+
+	files, err := embeddedLocales.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		b, err := embeddedLocales.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var messages Messages
+		if err := json.Unmarshal(b, &messages); err != nil {
+			return nil, fmt.Errorf("invalid locale file %s: %v", file.Name(), err)
+		}
+		c.messages[strings.TrimSuffix(file.Name(), path.Ext(file.Name()))] = messages
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Locales returns the sorted locales with messages in the catalog.
+func (c *Catalog) Locales() []string {
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+// Message returns the localized message of a FieldError. The locale is matched from the most specific to the
+// most general (e.g. "es-PE" and then "es") and then the fallback locale is used, if none of them have a message
+// for the code the original message of the error is returned.
+func (c *Catalog) Message(locale string, fe *models.FieldError) string {
+	template, ok := c.template(locale, fe.Code)
+	if !ok {
+		return fe.Message
+	}
+
+	replacements := []string{"{path}", fe.Path, "{value}", formatValue(fe.Value)}
+	for key, value := range fe.Params {
+		replacements = append(replacements, "{"+key+"}", formatValue(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// Localize returns the FieldErrors of err (check models.FieldErrors) with their messages in the locale,
+// the FieldErrors of err are not modified.
+func (c *Catalog) Localize(locale string, err error) []*models.FieldError {
+	fieldErrors := models.FieldErrors(err)
+	localized := make([]*models.FieldError, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		l := *fe
+		l.Message = c.Message(locale, fe)
+		localized = append(localized, &l)
+	}
+	return localized
+}
+
+// template looks for the message template of the code in the locale, its parent locales and the fallback locale.
+func (c *Catalog) template(locale string, code models.ErrorCode) (string, bool) {
+	locale = normalizeLocale(locale)
+	for locale != "" {
+		if template, ok := c.messages[locale][code]; ok {
+			return template, true
+		}
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	template, ok := c.messages[c.fallbackLocale][code]
+	return template, ok
+}
+
+// normalizeLocale converts a locale to lowercase with "-" as separator (e.g. "pt_BR" to "pt-br").
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// formatValue formats the value of a placeholder, the nil values are formatted as an empty string.
+func formatValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,139 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+// errorCodes are all the codes of the FieldErrors, every embedded locale must have a message for them
+var errorCodes = []models.ErrorCode{
+	models.RequiredCode,
+	models.InvalidValueCode,
+	models.UnknownFieldCode,
+	models.InvalidOperatorCode,
+	models.TypeMismatchCode,
+	models.LimitExceededCode,
+	models.UnindexedFilterCode,
+	models.UnindexedSortCode,
+}
+
+func TestEmbeddedLocalesAreComplete(t *testing.T) {
+	c, err := NewCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.Locales(), []string{"en", "es", "pt"}; !slices.Equal(got, want) {
+		t.Fatalf("Locales() = %v, want %v", got, want)
+	}
+	for _, locale := range c.Locales() {
+		for _, code := range errorCodes {
+			if message := c.messages[locale][code]; message == "" {
+				t.Errorf("locale %s has no message for %s", locale, code)
+			}
+		}
+		for code := range c.messages[locale] {
+			if !slices.Contains(errorCodes, code) {
+				t.Errorf("locale %s has a message for the unknown code %s", locale, code)
+			}
+		}
+	}
+}
+
+func TestCatalogMessage(t *testing.T) {
+	unknownField := models.NewFieldError("query.filters[0].conditions[0].field", models.UnknownFieldCode, "email", "invalid field: email")
+	limitExceeded := models.NewFieldError("pagination.limit", models.LimitExceededCode, uint(2000), "limit must be less than 1000").
+		WithParam("limit", uint(1000))
+	unknownCode := models.NewFieldError("query", models.ErrorCode("custom"), nil, "custom message")
+
+	tests := []struct {
+		name   string
+		opts   []CatalogOption
+		locale string
+		fe     *models.FieldError
+		want   string
+	}{
+		{name: "english", locale: "en", fe: unknownField, want: "The field email doesn't exist or cannot be used"},
+		{name: "spanish", locale: "es", fe: unknownField, want: "El campo email no existe o no puede ser usado"},
+		{name: "portuguese", locale: "pt", fe: unknownField, want: "O campo email não existe ou não pode ser usado"},
+		{name: "params", locale: "es", fe: limitExceeded, want: "El valor 2000 supera el límite de 1000"},
+		{name: "region falls back to the language", locale: "pt-BR", fe: limitExceeded, want: "O valor 2000 excede o limite de 1000"},
+		{name: "underscore separator", locale: "es_PE", fe: unknownField, want: "El campo email no existe o no puede ser usado"},
+		{name: "case insensitive locale", locale: " ES ", fe: unknownField, want: "El campo email no existe o no puede ser usado"},
+		{name: "unknown locale falls back to english", locale: "fr", fe: unknownField, want: "The field email doesn't exist or cannot be used"},
+		{name: "empty locale falls back to english", locale: "", fe: unknownField, want: "The field email doesn't exist or cannot be used"},
+		{
+			name:   "fallback locale",
+			opts:   []CatalogOption{WithFallbackLocale("pt")},
+			locale: "fr",
+			fe:     unknownField,
+			want:   "O campo email não existe ou não pode ser usado",
+		},
+		{name: "unknown code keeps the message", locale: "es", fe: unknownCode, want: "custom message"},
+		{
+			name:   "custom messages replace the embedded ones",
+			opts:   []CatalogOption{WithMessages("es", Messages{models.UnknownFieldCode: "Campo desconocido en {path}"})},
+			locale: "es",
+			fe:     unknownField,
+			want:   "Campo desconocido en query.filters[0].conditions[0].field",
+		},
+		{
+			name:   "custom messages keep the other embedded ones",
+			opts:   []CatalogOption{WithMessages("es", Messages{models.UnknownFieldCode: "Campo desconocido"})},
+			locale: "es",
+			fe:     limitExceeded,
+			want:   "El valor 2000 supera el límite de 1000",
+		},
+		{
+			name:   "new locale",
+			opts:   []CatalogOption{WithMessages("fr", Messages{models.UnknownFieldCode: "Le champ {value} n'existe pas"})},
+			locale: "fr-CA",
+			fe:     unknownField,
+			want:   "Le champ email n'existe pas",
+		},
+		{
+			name:   "new locale falls back for the missing codes",
+			opts:   []CatalogOption{WithMessages("fr", Messages{models.UnknownFieldCode: "Le champ {value} n'existe pas"})},
+			locale: "fr",
+			fe:     limitExceeded,
+			want:   "The value 2000 exceeds the limit of 1000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCatalog(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Message(tt.locale, tt.fe); got != tt.want {
+				t.Errorf("Message(%q) = %q, want %q", tt.locale, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogLocalize(t *testing.T) {
+	c, err := NewCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := models.NewFieldError("query.logical", models.InvalidValueCode, "xor", "invalid logical operator: xor")
+	err = models.ValidationErrors{original, errors.New("plain error")}
+
+	localized := c.LocalizeContext(ContextWithLocale(context.Background(), "es"), err)
+	if len(localized) != 2 {
+		t.Fatalf("LocalizeContext() = %v, want 2 errors", localized)
+	}
+	if localized[0].Message != "Este valor no es válido" || localized[0].Path != "query.logical" || localized[0].Code != models.InvalidValueCode {
+		t.Errorf("localized[0] = %+v", localized[0])
+	}
+	if localized[1].Message != "Este valor no es válido" {
+		t.Errorf("localized[1].Message = %q, want the message of invalid_value", localized[1].Message)
+	}
+	if original.Message != "invalid logical operator: xor" {
+		t.Errorf("the original error was modified: %q", original.Message)
+	}
+}
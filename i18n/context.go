@@ -0,0 +1,28 @@
+package i18n
+
+import (
+	"context"
+
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+// localeKey is the key of the locale in the context.
+type localeKey struct{}
+
+// ContextWithLocale returns a copy of ctx with the locale of the messages (e.g. the locale
+// of the Accept-Language header of the request).
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale of the context, an empty string if it's not set
+// (the Catalog uses its fallback locale for it).
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// LocalizeContext is Localize using the locale of the context (check ContextWithLocale).
+func (c *Catalog) LocalizeContext(ctx context.Context, err error) []*models.FieldError {
+	return c.Localize(LocaleFromContext(ctx), err)
+}
@@ -0,0 +1,4 @@
+// Package i18n provides the localized messages of the validation errors
+// (models.FieldError) in English, Spanish and Portuguese, more locales can be
+// added to a Catalog.
+package i18n
@@ -0,0 +1,69 @@
+package searcher_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+func TestIndexLintModes(t *testing.T) {
+	criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+		Field: "notes", Operator: models.EqualsOperator, Value: "a",
+	}}}}}}
+
+	tests := []struct {
+		name         string
+		opts         []searcher.Option
+		wantWarnings int
+		wantErr      bool
+	}{
+		{name: "warn by default", wantWarnings: 1},
+		{name: "off", opts: []searcher.Option{searcher.WithIndexLint(searcher.IndexLintOff)}},
+		{name: "reject", opts: []searcher.Option{searcher.WithIndexLint(searcher.IndexLintReject)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qt, err := searcher.NewQueryTranslator(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := qt.AddValidFieldsSet(models.ValidFields{
+				EntityName: "orders",
+				Fields: map[string]models.FieldMetaData{
+					"status": {Type: models.String},
+					"notes":  {Type: models.String},
+				},
+				Indexes: []models.Index{{Name: "status", Keys: []models.IndexKey{{Field: "status", Order: models.ASCOrder}}}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			query, err := qt.ToMongo("orders", criteria, nil)
+			if tt.wantErr {
+				fieldErrors := models.FieldErrors(err)
+				if !errors.Is(err, sentinels.ErrValidation) || len(fieldErrors) != 1 || fieldErrors[0].Code != models.UnindexedFilterCode {
+					t.Fatalf("ToMongo() error = %v, want an unindexed_filter validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := query.GetWarnings(); len(got) != tt.wantWarnings {
+				t.Errorf("GetWarnings() = %v, want %d warnings", got, tt.wantWarnings)
+			}
+
+			// LintCriteria reports the warnings whatever the mode of the translator
+			warnings, err := qt.LintCriteria("orders", criteria, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(warnings) != 1 || warnings[0].Path != "query.filters" {
+				t.Errorf("LintCriteria() = %v, want an unindexed_filter warning", warnings)
+			}
+		})
+	}
+}
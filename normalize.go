@@ -0,0 +1,294 @@
+package searcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Normalize returns the canonical form of the criteria for the entity, the semantically identical criteria
+// have the same canonical form (check Hash):
+//
+// - the defaults are applied as in PrepareCriteria and the logical operators and the orders are lowercased
+//
+// - the aliases are replaced by the names of the fields
+//
+// - the conditions of each filter and the filters of the query are sorted and deduplicated (their order doesn't
+// change the results) as the values of the "in" and "not_in" conditions
+//
+// - the absolute dates with time are converted to time.Time values in UTC, the whole days to models.DateValue values
+// (so the same day has the same form in any layout) and the relative dates like "now-7d" are kept as they are
+//
+// - the numbers of the Number fields are converted to json.Number values in their shortest form (e.g. 5, 5.0 and
+// json.Number("5.0") are json.Number("5"))
+//
+// - the valid hexadecimal strings of the ObjectID fields and the strings of the case insensitive fields are
+// lowercased and the geo values are decoded
+//
+// The criteria is validated as in ValidateCriteria and the normalized criteria can be translated as the original.
+func (ca *QueryTranslator) Normalize(validMapEntityName string, criteria models.Criteria) (models.Criteria, error) {
+	return ca.NormalizeCtx(context.Background(), validMapEntityName, criteria)
+}
+
+// NormalizeCtx is Normalize for the principal of the context, the fields that its roles cannot see are reported
+// as unknown fields and the errors are localized to the locale of the context (check ValidateCriteriaCtx).
+func (ca *QueryTranslator) NormalizeCtx(ctx context.Context, validMapEntityName string, criteria models.Criteria) (models.Criteria, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Criteria{}, err
+	}
+	vf, ok := ca.fieldSets.Lookup(validMapEntityName)
+	if !ok {
+		return models.Criteria{}, fmt.Errorf("%w: %w: %s", sentinels.ErrValidation, ErrFieldSetNotFound, validMapEntityName)
+	}
+	vf = ca.withEntityLimits(vf).ForRoles(rolesFromContext(ctx))
+
+	normalized, err := ca.normalize(vf, criteria)
+	return normalized, ca.localizeError(ctx, err)
+}
+
+// Hash returns a stable fingerprint (a hexadecimal SHA-256) of the entity, the normalized criteria (check Normalize)
+// and the normalized super filters, e.g. for use it as the key of a cache of search results. The semantically
+// identical criteria have the same hash.
+//
+// The relative dates (e.g. "now-7d") are hashed as expressions and not as the dates they resolve to, so the hash
+// doesn't change while the window of the query moves: the results cached with a criteria that has relative dates
+// must expire (e.g. a TTL shorter than the precision of the dates) or they will be stale.
+//
+// The hash of an entity with policies fails with ErrPrincipalRequired (or the policies are skipped if they are
+// lenient), use HashCtx for include their filters.
+func (ca *QueryTranslator) Hash(validMapEntityName string, criteria models.Criteria, superFilters ...models.SuperFilter) (string, error) {
+	return ca.HashCtx(context.Background(), validMapEntityName, criteria, superFilters...)
+}
+
+// HashCtx is Hash for the principal of the context, the filters of the policies of the entity are added to the
+// super filters as in Translate so the results cached for a principal are not shared with the other principals.
+func (ca *QueryTranslator) HashCtx(ctx context.Context, validMapEntityName string, criteria models.Criteria, superFilters ...models.SuperFilter) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	vf, ok := ca.fieldSets.Lookup(validMapEntityName)
+	if !ok {
+		return "", fmt.Errorf("%w: %w: %s", sentinels.ErrValidation, ErrFieldSetNotFound, validMapEntityName)
+	}
+	vf = ca.withEntityLimits(vf)
+	superFilterFields := vf.Fields
+	vf = vf.ForRoles(rolesFromContext(ctx))
+
+	superFilters, err := ca.applyPolicies(ctx, validMapEntityName, superFilters)
+	if err != nil {
+		return "", err
+	}
+	normalized, err := ca.normalize(vf, criteria)
+	if err != nil {
+		return "", ca.localizeError(ctx, err)
+	}
+	if err := validateSuperFilters(superFilters); err != nil {
+		return "", ca.localizeError(ctx, err)
+	}
+
+	b, err := json.Marshal(struct {
+		Entity       string               `json:"entity"`
+		Criteria     models.Criteria      `json:"criteria"`
+		SuperFilters []models.SuperFilter `json:"super_filters"`
+	}{
+		Entity:       validMapEntityName,
+		Criteria:     normalized,
+		SuperFilters: ca.normalizeSuperFilters(superFilterFields, superFilters),
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot hash the criteria: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalize returns the canonical form of a criteria against the valid fields (with the limits already resolved).
+func (ca *QueryTranslator) normalize(vf models.ValidFields, criteria models.Criteria) (models.Criteria, error) {
+	// The logical operators and the orders are case insensitive
+	criteria.Query.Logical = models.Logical(strings.ToLower(criteria.Query.Logical.String()))
+	filters := make(models.Filters, 0, len(criteria.Query.Filters))
+	for _, filter := range criteria.Query.Filters {
+		filter.Logical = models.Logical(strings.ToLower(filter.Logical.String()))
+		filters = append(filters, filter)
+	}
+	criteria.Query.Filters = filters
+	sorts := make(models.Sorts, 0, len(criteria.Query.Sorts))
+	for _, s := range criteria.Query.Sorts {
+		s.Order = models.Order(strings.ToLower(s.Order.String()))
+		sorts = append(sorts, s)
+	}
+	criteria.Query.Sorts = sorts
+
+	normalized := ca.prepareCriteria(&criteria, vf.Limits)
+	if err := ca.validateCriteria(vf, *normalized); err != nil {
+		return models.Criteria{}, err
+	}
+
+	for filterIndex, filter := range normalized.Query.Filters {
+		conditions := make(models.Conditions, 0, len(filter.Conditions))
+		for _, condition := range filter.Conditions {
+			fieldMetaData, _ := vf.FilterField(condition.Field.String())
+			condition.Field = fieldMetaData.Field
+			condition.Value = ca.normalizeValue(fieldMetaData, condition.Operator, condition.Value)
+			conditions = append(conditions, condition)
+		}
+		filter.Conditions = canonicalConditions(conditions)
+		normalized.Query.Filters[filterIndex] = filter
+	}
+	normalized.Query.Filters = canonicalFilters(normalized.Query.Filters)
+	for sortIndex, s := range normalized.Query.Sorts {
+		fieldMetaData, _ := vf.SortField(s.Field)
+		normalized.Query.Sorts[sortIndex].Field = fieldMetaData.Field.String()
+	}
+
+	// The deduplication can leave a single filter or condition so the default logical operators are applied again
+	return *ca.prepareCriteria(normalized, vf.Limits), nil
+}
+
+// normalizeSuperFilters returns the super filters with the operator set, their values normalized as the values of
+// the conditions, sorted and deduplicated. The fields are all the fields of the entity (not restricted by roles).
+func (ca *QueryTranslator) normalizeSuperFilters(fields map[string]models.FieldMetaData, superFilters []models.SuperFilter) []models.SuperFilter {
+	normalized := make([]models.SuperFilter, 0, len(superFilters))
+	for _, superFilter := range superFilters {
+		condition := superFilter.Condition()
+		fieldMetaData := fields[superFilter.Field]
+		fieldMetaData.Field = condition.Field
+		normalized = append(normalized, models.SuperFilter{
+			Field:    superFilter.Field,
+			Operator: condition.Operator,
+			Value:    ca.normalizeValue(fieldMetaData, condition.Operator, condition.Value),
+		})
+	}
+	slices.SortStableFunc(normalized, func(a, b models.SuperFilter) int { return strings.Compare(canonicalKey(a), canonicalKey(b)) })
+	return slices.CompactFunc(normalized, func(a, b models.SuperFilter) bool { return canonicalKey(a) == canonicalKey(b) })
+}
+
+// normalizeValue returns the canonical form of a validated value of a condition (check Normalize).
+func (ca *QueryTranslator) normalizeValue(fieldMetaData models.FieldMetaData, operator models.Operator, v interface{}) interface{} {
+	switch {
+	case v == nil, operator.Equals(models.ExistsOperator):
+		return v
+	case operator.IsGeo():
+		if geoValue, err := geoNodeValue(operator, v); err == nil {
+			return geoValue
+		}
+		return v
+	case operator.IsList():
+		values, ok := models.ListValues(v)
+		if !ok {
+			return v
+		}
+		normalized := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			normalized = append(normalized, ca.normalizeScalar(fieldMetaData, value))
+		}
+		slices.SortStableFunc(normalized, func(a, b interface{}) int { return strings.Compare(canonicalKey(a), canonicalKey(b)) })
+		return slices.CompactFunc(normalized, func(a, b interface{}) bool { return canonicalKey(a) == canonicalKey(b) })
+	}
+	return ca.normalizeScalar(fieldMetaData, v)
+}
+
+// normalizeScalar returns the canonical form of a single value of a field.
+func (ca *QueryTranslator) normalizeScalar(fieldMetaData models.FieldMetaData, v interface{}) interface{} {
+	s, isString := v.(string)
+	switch {
+	case fieldMetaData.Type.Equals(models.Date):
+		// The relative dates are kept as expressions, resolving them would change the hash at every call
+		if isString {
+			if _, err := ca.dateFormatter.FromRelativeString(s); err == nil {
+				return s
+			}
+		}
+		date, err := ca.parseDate(v)
+		if err != nil {
+			return v
+		}
+		if date.IsDay() {
+			return date
+		}
+		return date.Time.UTC()
+	case fieldMetaData.Type.Equals(models.Number):
+		return canonicalNumber(v)
+	case isString && fieldMetaData.Type.Equals(models.ObjectID):
+		// The invalid ObjectIds are reported by the validation, only the valid ones have a canonical form
+		if objectID, err := primitive.ObjectIDFromHex(s); err == nil {
+			return objectID.Hex()
+		}
+		return s
+	case isString && fieldMetaData.IsCaseInsensitive:
+		return strings.ToLower(s)
+	}
+	return v
+}
+
+// canonicalNumber returns the numbers of any type as a json.Number in their shortest form, the integers without
+// exponent or decimals (e.g. 5, 5.0, 5e0 and json.Number("5.0") are json.Number("5")). The other values are
+// returned as they are.
+func canonicalNumber(v interface{}) interface{} {
+	var f float64
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return json.Number(strconv.FormatInt(i, 10))
+		}
+		parsed, err := value.Float64()
+		if err != nil {
+			return v
+		}
+		f = parsed
+	case float64:
+		f = value
+	case float32:
+		f = float64(value)
+	default:
+		number := reflect.ValueOf(v)
+		switch number.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return json.Number(strconv.FormatInt(number.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return json.Number(strconv.FormatUint(number.Uint(), 10))
+		}
+		return v
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return v
+	}
+	// The integers are exact in a float64 up to 2^53
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return json.Number(strconv.FormatInt(int64(f), 10))
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// canonicalConditions sorts the conditions of a filter by their canonical key removing the duplicates.
+func canonicalConditions(conditions models.Conditions) models.Conditions {
+	slices.SortStableFunc(conditions, func(a, b models.Condition) int { return strings.Compare(canonicalKey(a), canonicalKey(b)) })
+	return slices.CompactFunc(conditions, func(a, b models.Condition) bool { return canonicalKey(a) == canonicalKey(b) })
+}
+
+// canonicalFilters sorts the filters of a query by their canonical key removing the duplicates.
+func canonicalFilters(filters models.Filters) models.Filters {
+	slices.SortStableFunc(filters, func(a, b models.Filter) int { return strings.Compare(canonicalKey(a), canonicalKey(b)) })
+	return slices.CompactFunc(filters, func(a, b models.Filter) bool { return canonicalKey(a) == canonicalKey(b) })
+}
+
+// canonicalKey returns the JSON encoding of a value for compare it with other values, the maps are encoded with
+// their keys sorted so the key is stable.
+func canonicalKey(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+	return string(b)
+}
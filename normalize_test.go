@@ -0,0 +1,210 @@
+package searcher_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/date"
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+func newOrdersTranslator(t *testing.T) *searcher.QueryTranslator {
+	t.Helper()
+	formatter, err := date.NewFormatter(date.WithLayouts(time.RFC3339, time.DateOnly, "02/01/2006"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	qt, err := searcher.NewQueryTranslator(searcher.WithDateFormatter(formatter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "orders",
+		Fields: map[string]models.FieldMetaData{
+			"id":         {Type: models.ObjectID},
+			"total":      {Type: models.Number},
+			"created_at": {Type: models.Date},
+			"status":     {Type: models.String},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return qt
+}
+
+// conditions returns a criteria with a single "and" filter of the conditions
+func conditions(conditions ...models.Condition) models.Criteria {
+	return models.Criteria{Query: models.Query{Filters: models.Filters{{Logical: models.ANDLogical, Conditions: conditions}}}}
+}
+
+func TestHashSemanticallyIdenticalCriteria(t *testing.T) {
+	qt := newOrdersTranslator(t)
+	tests := []struct {
+		name string
+		a, b models.Criteria
+	}{
+		{
+			name: "object id case",
+			a:    conditions(models.Condition{Field: "id", Operator: models.EqualsOperator, Value: "65A1B2C3D4E5F60718293A4B"}),
+			b:    conditions(models.Condition{Field: "id", Operator: models.EqualsOperator, Value: "65a1b2c3d4e5f60718293a4b"}),
+		},
+		{
+			name: "day layouts",
+			a:    conditions(models.Condition{Field: "created_at", Operator: models.EqualsOperator, Value: "2024-03-15"}),
+			b:    conditions(models.Condition{Field: "created_at", Operator: models.EqualsOperator, Value: "15/03/2024"}),
+		},
+		{
+			name: "instant time zones",
+			a:    conditions(models.Condition{Field: "created_at", Operator: models.GreaterThan, Value: "2024-03-15T10:00:00Z"}),
+			b:    conditions(models.Condition{Field: "created_at", Operator: models.GreaterThan, Value: "2024-03-15T12:00:00+02:00"}),
+		},
+		{
+			name: "integer and decimal numbers",
+			a:    conditions(models.Condition{Field: "total", Operator: models.EqualsOperator, Value: 5}),
+			b:    conditions(models.Condition{Field: "total", Operator: models.EqualsOperator, Value: 5.0}),
+		},
+		{
+			name: "json numbers",
+			a:    conditions(models.Condition{Field: "total", Operator: models.GreaterThan, Value: json.Number("5.0")}),
+			b:    conditions(models.Condition{Field: "total", Operator: models.GreaterThan, Value: int64(5)}),
+		},
+		{
+			name: "list order, duplicates and number types",
+			a:    conditions(models.Condition{Field: "total", Operator: models.InOperator, Value: []interface{}{3, 1.5, 3.0}}),
+			b:    conditions(models.Condition{Field: "total", Operator: models.InOperator, Value: []float64{1.5, 3}}),
+		},
+		{
+			name: "conditions order",
+			a: conditions(
+				models.Condition{Field: "status", Operator: models.EqualsOperator, Value: "paid"},
+				models.Condition{Field: "total", Operator: models.GreaterThan, Value: 10},
+			),
+			b: conditions(
+				models.Condition{Field: "total", Operator: models.GreaterThan, Value: 10.0},
+				models.Condition{Field: "status", Operator: models.EqualsOperator, Value: "paid"},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := qt.Hash("orders", tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := qt.Hash("orders", tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a != b {
+				t.Errorf("Hash() = %s and %s, want the same hash", a, b)
+			}
+		})
+	}
+}
+
+func TestHashDifferentCriteria(t *testing.T) {
+	qt := newOrdersTranslator(t)
+	tests := []struct {
+		name string
+		a, b models.Criteria
+	}{
+		{
+			name: "different days",
+			a:    conditions(models.Condition{Field: "created_at", Operator: models.EqualsOperator, Value: "2024-03-15"}),
+			b:    conditions(models.Condition{Field: "created_at", Operator: models.EqualsOperator, Value: "16/03/2024"}),
+		},
+		{
+			name: "day and its first instant",
+			a:    conditions(models.Condition{Field: "created_at", Operator: models.EqualsOperator, Value: "2024-03-15"}),
+			b:    conditions(models.Condition{Field: "created_at", Operator: models.EqualsOperator, Value: "2024-03-15T00:00:00Z"}),
+		},
+		{
+			name: "different numbers",
+			a:    conditions(models.Condition{Field: "total", Operator: models.EqualsOperator, Value: 5}),
+			b:    conditions(models.Condition{Field: "total", Operator: models.EqualsOperator, Value: 5.5}),
+		},
+		{
+			name: "case sensitive strings",
+			a:    conditions(models.Condition{Field: "status", Operator: models.EqualsOperator, Value: "Paid"}),
+			b:    conditions(models.Condition{Field: "status", Operator: models.EqualsOperator, Value: "paid"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := qt.Hash("orders", tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := qt.Hash("orders", tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a == b {
+				t.Errorf("Hash() = %s for both criteria, want different hashes", a)
+			}
+		})
+	}
+}
+
+func TestHashIsStable(t *testing.T) {
+	criteria := conditions(
+		models.Condition{Field: "id", Operator: models.InOperator, Value: []string{"65A1B2C3D4E5F60718293A4B", "65a1b2c3d4e5f60718293a4c"}},
+		models.Condition{Field: "created_at", Operator: models.GreaterAndEqualsThan, Value: "2024-03-15"},
+		models.Condition{Field: "total", Operator: models.LessThan, Value: json.Number("99.90")},
+	)
+	superFilter := models.SuperFilter{Field: "status", Value: "paid"}
+
+	want, err := newOrdersTranslator(t).Hash("orders", criteria, superFilter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The hash doesn't depend on the translator instance or on the order of the maps
+	for i := 0; i < 10; i++ {
+		got, err := newOrdersTranslator(t).Hash("orders", criteria, superFilter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("Hash() = %s, want %s", got, want)
+		}
+	}
+}
+
+func TestNormalizedCriteriaTranslatesAsTheOriginal(t *testing.T) {
+	qt := newOrdersTranslator(t)
+	tests := []struct {
+		name      string
+		condition models.Condition
+	}{
+		{name: "object id", condition: models.Condition{Field: "id", Operator: models.EqualsOperator, Value: "65A1B2C3D4E5F60718293A4B"}},
+		{name: "day", condition: models.Condition{Field: "created_at", Operator: models.LessAndEqualsThan, Value: "15/03/2024"}},
+		{name: "day equality", condition: models.Condition{Field: "created_at", Operator: models.EqualsOperator, Value: "2024-03-15"}},
+		{name: "days list", condition: models.Condition{Field: "created_at", Operator: models.InOperator, Value: []string{"2024-03-15", "16/03/2024"}}},
+		{name: "numbers", condition: models.Condition{Field: "total", Operator: models.InOperator, Value: []interface{}{5.0, json.Number("7")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria := conditions(tt.condition)
+			normalized, err := qt.Normalize("orders", criteria)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := qt.ToElastic("orders", criteria, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := qt.ToElastic("orders", normalized, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("ToElastic(normalized) = %s, want %s", got, want)
+			}
+			if _, err := qt.ToMongo("orders", normalized, nil); err != nil {
+				t.Errorf("ToMongo(normalized) error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package searcher
+
+import (
+	"time"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/domain/ports"
+	"github.com/solrac97gr/searcher/i18n"
+)
+
+const (
+	// DefaultKeywordSuffix is the suffix added to the analyzed fields in Elasticsearch for use their raw value
+	DefaultKeywordSuffix = ".raw"
+	// DefaultTiebreakerField is the field added at the end of the Elasticsearch sorts for a stable pagination
+	DefaultTiebreakerField = "bayonet_tracking_id"
+)
+
+// IndexLintMode is the behaviour of the index lint of the translators.
+type IndexLintMode int
+
+const (
+	// IndexLintWarn adds the lint warnings to the query (check models.MongoQuery.GetWarnings)
+	IndexLintWarn IndexLintMode = iota
+	// IndexLintOff disables the index lint
+	IndexLintOff
+	// IndexLintReject returns the lint warnings as a validation error
+	IndexLintReject
+)
+
+// Option configures a QueryTranslator, check the With* functions.
+type Option func(*options)
+
+// options are the settings collected from the Option functions before build the QueryTranslator.
+type options struct {
+	dateFormatter    ports.DateFormatter
+	clock            func() time.Time
+	paginationLimits models.PaginationLimits
+	complexityLimits models.ComplexityLimits
+	costWeights      models.CostWeights
+	defaultLogical   models.Logical
+	keywordSuffix    string
+	tiebreaker       *models.Sort
+	indexLint        IndexLintMode
+	lenientPolicies  bool
+	tracer           ports.Tracer
+	catalog          *i18n.Catalog
+	dialects         []ports.Dialect
+}
+
+func defaultOptions() options {
+	return options{
+		clock:            time.Now,
+		paginationLimits: models.DefaultPaginationLimits,
+		costWeights:      models.DefaultCostWeights,
+		defaultLogical:   DefaultLogicOperator,
+		keywordSuffix:    DefaultKeywordSuffix,
+		tiebreaker: &models.Sort{
+			Field: DefaultTiebreakerField,
+			Order: models.ASCOrder,
+		},
+		indexLint: IndexLintWarn,
+	}
+}
+
+// WithDateFormatter replaces the date.Formatter used for convert the values of the Date fields.
+func WithDateFormatter(df ports.DateFormatter) Option {
+	return func(o *options) {
+		o.dateFormatter = df
+	}
+}
+
+// WithClock sets the clock used by the default date formatter for resolve the
+// relative date expressions (e.g. "now-7d"). It's ignored when a custom
+// formatter is provided with WithDateFormatter.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.clock = now
+	}
+}
+
+// WithPaginationLimits sets the default limit and the maximum limit and offset
+// accepted by the translators.
+func WithPaginationLimits(limits models.PaginationLimits) Option {
+	return func(o *options) {
+		o.paginationLimits = limits
+	}
+}
+
+// WithComplexityLimits sets the maximum number of filters, conditions, sorts, list values and the
+// maximum cost accepted by the translators, by default there are no limits.
+func WithComplexityLimits(limits models.ComplexityLimits) Option {
+	return func(o *options) {
+		o.complexityLimits = limits
+	}
+}
+
+// WithCostWeights sets the weights used for compute the cost of the criteria (check
+// models.Criteria.Cost), by default models.DefaultCostWeights.
+func WithCostWeights(weights models.CostWeights) Option {
+	return func(o *options) {
+		o.costWeights = weights
+	}
+}
+
+// WithDefaultLogical sets the logical operator used when a query or a filter
+// only have one element (check PrepareCriteria), by default "and".
+func WithDefaultLogical(logical models.Logical) Option {
+	return func(o *options) {
+		o.defaultLogical = logical
+	}
+}
+
+// WithKeywordSuffix sets the suffix added to the analyzed fields in
+// Elasticsearch for filter and sort by their raw value, by default ".raw".
+func WithKeywordSuffix(suffix string) Option {
+	return func(o *options) {
+		o.keywordSuffix = suffix
+	}
+}
+
+// WithTiebreaker sets the sort added at the end of the Elasticsearch sorts, by
+// default "bayonet_tracking_id" asc. An empty field disables the tiebreaker.
+func WithTiebreaker(field string, order models.Order) Option {
+	return func(o *options) {
+		if field == "" {
+			o.tiebreaker = nil
+			return
+		}
+		o.tiebreaker = &models.Sort{Field: field, Order: order}
+	}
+}
+
+// WithIndexLint sets what ToMongo does when the criteria cannot use the indexes of the entity (check
+// models.Criteria.LintIndexes), by default IndexLintWarn.
+func WithIndexLint(mode IndexLintMode) Option {
+	return func(o *options) {
+		o.indexLint = mode
+	}
+}
+
+// WithLenientPolicies skips the policies when the context has no principal or a policy is missing principal data,
+// by default the translations of the entities with policies fail with ErrPrincipalRequired. It must be used only
+// when the callers without principal (e.g. the internal jobs) can see all the documents of the entities.
+func WithLenientPolicies() Option {
+	return func(o *options) {
+		o.lenientPolicies = true
+	}
+}
+
+// WithTracer sets the Tracer notified of every translation (e.g. for create a span per translation), by
+// default the translations are not traced.
+func WithTracer(tracer ports.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// WithCatalog sets the i18n.Catalog used for localize the validation errors of the context-aware methods
+// (e.g. ToMongoCtx) to the locale of the context (check i18n.ContextWithLocale).
+func WithCatalog(catalog *i18n.Catalog) Option {
+	return func(o *options) {
+		o.catalog = catalog
+	}
+}
+
+// WithDialect registers a dialect for translate the criteria to a new backend with QueryTranslator.Translate.
+func WithDialect(dialect ports.Dialect) Option {
+	return func(o *options) {
+		o.dialects = append(o.dialects, dialect)
+	}
+}
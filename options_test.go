@@ -0,0 +1,125 @@
+package searcher_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/date"
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+func TestTranslatorOptions(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+	clock := searcher.WithClock(func() time.Time { return now })
+	formatter, err := date.NewFormatter(date.WithLayouts("02/01/2006 15:04"), date.WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	createdAfter := func(v interface{}) models.Criteria {
+		return models.Criteria{Query: models.Query{
+			Filters: models.Filters{{Conditions: models.Conditions{{Field: "created_at", Operator: models.GreaterThan, Value: v}}}},
+			Sorts:   models.Sorts{{Field: "name", Order: models.DESCOrder}},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		opts     []searcher.Option
+		criteria models.Criteria
+		want     string
+	}{
+		{
+			name:     "defaults",
+			opts:     []searcher.Option{clock},
+			criteria: createdAfter("2024-03-01T00:00:00Z"),
+			want:     `{"from":0,"query":{"bool":{"must":[{"range":{"created_at":{"gt":"2024-03-01T00:00:00Z"}}}]}},"size":50,"sort":[{"name.raw":{"order":"desc"}},{"bayonet_tracking_id":{"order":"asc"}}]}`,
+		},
+		{
+			name:     "clock",
+			opts:     []searcher.Option{clock},
+			criteria: createdAfter("now-1d"),
+			want:     `{"from":0,"query":{"bool":{"must":[{"range":{"created_at":{"gt":"2024-03-14T10:00:00Z"}}}]}},"size":50,"sort":[{"name.raw":{"order":"desc"}},{"bayonet_tracking_id":{"order":"asc"}}]}`,
+		},
+		{
+			name:     "date formatter",
+			opts:     []searcher.Option{searcher.WithDateFormatter(formatter)},
+			criteria: createdAfter("01/03/2024 08:30"),
+			want:     `{"from":0,"query":{"bool":{"must":[{"range":{"created_at":{"gt":"2024-03-01T08:30:00Z"}}}]}},"size":50,"sort":[{"name.raw":{"order":"desc"}},{"bayonet_tracking_id":{"order":"asc"}}]}`,
+		},
+		{
+			name:     "keyword suffix",
+			opts:     []searcher.Option{clock, searcher.WithKeywordSuffix(".keyword")},
+			criteria: createdAfter("now"),
+			want:     `{"from":0,"query":{"bool":{"must":[{"range":{"created_at":{"gt":"2024-03-15T10:00:00Z"}}}]}},"size":50,"sort":[{"name.keyword":{"order":"desc"}},{"bayonet_tracking_id":{"order":"asc"}}]}`,
+		},
+		{
+			name:     "tiebreaker",
+			opts:     []searcher.Option{clock, searcher.WithTiebreaker("id", models.DESCOrder)},
+			criteria: createdAfter("now"),
+			want:     `{"from":0,"query":{"bool":{"must":[{"range":{"created_at":{"gt":"2024-03-15T10:00:00Z"}}}]}},"size":50,"sort":[{"name.raw":{"order":"desc"}},{"id":{"order":"desc"}}]}`,
+		},
+		{
+			name:     "without tiebreaker",
+			opts:     []searcher.Option{clock, searcher.WithTiebreaker("", models.ASCOrder)},
+			criteria: createdAfter("now"),
+			want:     `{"from":0,"query":{"bool":{"must":[{"range":{"created_at":{"gt":"2024-03-15T10:00:00Z"}}}]}},"size":50,"sort":[{"name.raw":{"order":"desc"}}]}`,
+		},
+		{
+			name:     "pagination limits",
+			opts:     []searcher.Option{clock, searcher.WithPaginationLimits(models.PaginationLimits{DefaultLimit: 20, MaximumLimit: 100, MaximumLimitOffsetSize: 1000})},
+			criteria: createdAfter("now"),
+			want:     `{"from":0,"query":{"bool":{"must":[{"range":{"created_at":{"gt":"2024-03-15T10:00:00Z"}}}]}},"size":20,"sort":[{"name.raw":{"order":"desc"}},{"bayonet_tracking_id":{"order":"asc"}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qt, err := searcher.NewQueryTranslator(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := qt.AddValidFieldsSet(models.ValidFields{
+				EntityName: "clients",
+				Fields: map[string]models.FieldMetaData{
+					"name":       {Type: models.String, IsAnalyzed: true},
+					"created_at": {Type: models.Date},
+				},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := qt.ToElastic("clients", tt.criteria, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ToElastic() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultLogicalOption(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []searcher.Option
+		want models.Logical
+	}{
+		{name: "default", want: models.ANDLogical},
+		{name: "or", opts: []searcher.Option{searcher.WithDefaultLogical(models.ORLogical)}, want: models.ORLogical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qt, err := searcher.NewQueryTranslator(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			criteria := qt.PrepareCriteria(&models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+				Field: "name", Operator: models.EqualsOperator, Value: "John",
+			}}}}}})
+			if criteria.Query.Logical != tt.want || criteria.Query.Filters[0].Logical != tt.want {
+				t.Errorf("logical = %s and %s, want %s", criteria.Query.Logical, criteria.Query.Filters[0].Logical, tt.want)
+			}
+		})
+	}
+}
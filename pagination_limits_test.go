@@ -0,0 +1,95 @@
+package searcher_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+func TestEntityPaginationLimits(t *testing.T) {
+	qt, err := searcher.NewQueryTranslator(searcher.WithPaginationLimits(models.PaginationLimits{
+		DefaultLimit:           20,
+		MaximumLimit:           1000,
+		MaximumLimitOffsetSize: 10000,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := map[string]models.FieldMetaData{"name": {Type: models.String}}
+	for _, vf := range []models.ValidFields{
+		{EntityName: "audit_logs", Fields: fields, Limits: models.PaginationLimits{MaximumLimit: 100}},
+		{EntityName: "exports", Fields: fields, Limits: models.PaginationLimits{DefaultLimit: 500, MaximumLimit: 5000, MaximumLimitOffsetSize: 50000}},
+		{EntityName: "clients", Fields: fields},
+	} {
+		if err := qt.AddValidFieldsSet(vf); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		entity     string
+		pagination models.Pagination
+		wantLimit  uint
+		wantPath   string
+	}{
+		{name: "translator default limit", entity: "audit_logs", wantLimit: 20},
+		{name: "entity maximum limit", entity: "audit_logs", pagination: models.Pagination{Limit: 100}, wantLimit: 100},
+		{name: "entity maximum limit exceeded", entity: "audit_logs", pagination: models.Pagination{Limit: 101}, wantPath: "pagination.limit"},
+		{name: "entity default limit", entity: "exports", wantLimit: 500},
+		{name: "entity limit above the translator limit", entity: "exports", pagination: models.Pagination{Limit: 5000, Offset: 40000}, wantLimit: 5000},
+		{name: "entity offset exceeded", entity: "exports", pagination: models.Pagination{Limit: 5000, Offset: 45001}, wantPath: "pagination.offset"},
+		{name: "translator limits", entity: "clients", pagination: models.Pagination{Limit: 1000}, wantLimit: 1000},
+		{name: "translator limit exceeded", entity: "clients", pagination: models.Pagination{Limit: 1001}, wantPath: "pagination.limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := qt.ToMongo(tt.entity, models.Criteria{Pagination: tt.pagination}, nil)
+			if tt.wantPath != "" {
+				fieldErrors := models.FieldErrors(err)
+				if !errors.Is(err, sentinels.ErrValidation) || len(fieldErrors) != 1 {
+					t.Fatalf("ToMongo() error = %v, want a validation error", err)
+				}
+				if fieldErrors[0].Path != tt.wantPath || fieldErrors[0].Code != models.LimitExceededCode {
+					t.Errorf("error = %s %s, want limit_exceeded in %s", fieldErrors[0].Path, fieldErrors[0].Code, tt.wantPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := query["limit"]; got != tt.wantLimit {
+				t.Errorf("limit = %v, want %d", got, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestPaginationLimitsOfEntity(t *testing.T) {
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "audit_logs",
+		Fields:     map[string]models.FieldMetaData{"name": {Type: models.String}},
+		Limits:     models.PaginationLimits{MaximumLimit: 100},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	limits, err := qt.PaginationLimits("audit_logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.PaginationLimits{DefaultLimit: 50, MaximumLimit: 100, MaximumLimitOffsetSize: 10000}
+	if limits != want {
+		t.Errorf("PaginationLimits() = %+v, want %+v", limits, want)
+	}
+	if _, err := qt.PaginationLimits("unknown"); !errors.Is(err, searcher.ErrFieldSetNotFound) {
+		t.Errorf("PaginationLimits() error = %v, want %v", err, searcher.ErrFieldSetNotFound)
+	}
+}
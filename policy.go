@@ -0,0 +1,152 @@
+package searcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+// ErrPrincipalRequired is returned by a Policy when the principal doesn't have the data needed for build its
+// filters, the translations fail with it unless the policies are lenient (check WithLenientPolicies).
+var ErrPrincipalRequired = errors.New("the principal data required by the policy is missing")
+
+// Principal is the caller of a translation, the policies use it for build the mandatory filters of the entities.
+type Principal struct {
+	TenantID string
+	UserID   string
+	Roles    []string
+	// Attributes are other data of the caller used by custom policies (e.g. the allowed regions)
+	Attributes map[string]interface{}
+}
+
+// HasRole checks if the principal has the role.
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// principalKey is the key of the principal in the context.
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx with the principal of the request.
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal of the context, ok is false if it's not set.
+func PrincipalFromContext(ctx context.Context) (principal Principal, ok bool) {
+	principal, ok = ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// rolesFromContext returns the roles of the principal of the context, none if it's not set.
+func rolesFromContext(ctx context.Context) []string {
+	principal, _ := PrincipalFromContext(ctx)
+	return principal.Roles
+}
+
+// Policy builds the mandatory filters of an entity for a principal, the filters are applied as super filters
+// (ANDed at the top level of the query) by every translator. It returns ErrPrincipalRequired when the principal
+// doesn't have the data needed (e.g. the tenant), any other error fails the translation.
+type Policy func(ctx context.Context, principal Principal) ([]models.SuperFilter, error)
+
+// TenantPolicy returns a Policy that restricts the documents to the tenant of the principal (field = TenantID).
+func TenantPolicy(field string) Policy {
+	return func(_ context.Context, principal Principal) ([]models.SuperFilter, error) {
+		if principal.TenantID == "" {
+			return nil, fmt.Errorf("%w: tenant id", ErrPrincipalRequired)
+		}
+		return []models.SuperFilter{{Field: field, Value: principal.TenantID}}, nil
+	}
+}
+
+// OwnerPolicy returns a Policy that restricts the documents to the user of the principal (field = UserID), the
+// principals with any of the bypass roles (e.g. "admin") can read the documents of all the users.
+func OwnerPolicy(field string, bypassRoles ...string) Policy {
+	return func(_ context.Context, principal Principal) ([]models.SuperFilter, error) {
+		for _, role := range bypassRoles {
+			if principal.HasRole(role) {
+				return nil, nil
+			}
+		}
+		if principal.UserID == "" {
+			return nil, fmt.Errorf("%w: user id", ErrPrincipalRequired)
+		}
+		return []models.SuperFilter{{Field: field, Value: principal.UserID}}, nil
+	}
+}
+
+// policyRegistry is a concurrency-safe registry of the policies by entity name.
+type policyRegistry struct {
+	mu       sync.RWMutex
+	policies map[string][]Policy
+}
+
+// newPolicyRegistry creates an empty policyRegistry.
+func newPolicyRegistry() *policyRegistry {
+	return &policyRegistry{policies: make(map[string][]Policy)}
+}
+
+// add appends the policies of an entity.
+func (r *policyRegistry) add(entityName string, policies ...Policy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policies[entityName] = append(slices.Clip(r.policies[entityName]), policies...)
+}
+
+// lookup returns the policies of an entity, the returned slice must not be modified.
+func (r *policyRegistry) lookup(entityName string) []Policy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.policies[entityName]
+}
+
+// AddPolicies registers policies for an entity, their filters are added to the super filters of every translation
+// of the entity with any dialect (check Translate).
+func (ca *QueryTranslator) AddPolicies(validMapEntityName string, policies ...Policy) error {
+	if validMapEntityName == "" {
+		return errors.New("the entity name of the policies cannot be empty")
+	}
+	for _, policy := range policies {
+		if policy == nil {
+			return fmt.Errorf("nil policy for entity: %s", validMapEntityName)
+		}
+	}
+	ca.policies.add(validMapEntityName, policies...)
+	return nil
+}
+
+// applyPolicies returns the super filters with the filters of the policies of the entity added. It fails with
+// ErrPrincipalRequired when the context has no principal or a policy returns it, unless the policies are lenient
+// then those policies are skipped.
+func (ca *QueryTranslator) applyPolicies(ctx context.Context, validMapEntityName string, superFilters []models.SuperFilter) ([]models.SuperFilter, error) {
+	policies := ca.policies.lookup(validMapEntityName)
+	if len(policies) == 0 {
+		return superFilters, nil
+	}
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		if ca.lenientPolicies {
+			return superFilters, nil
+		}
+		return nil, fmt.Errorf("%w: no principal in the context for entity: %s", ErrPrincipalRequired, validMapEntityName)
+	}
+
+	// The filters of the policies are added to a copy for not modify the slice of the caller
+	superFilters = slices.Clip(superFilters)
+	for _, policy := range policies {
+		filters, err := policy(ctx, principal)
+		if errors.Is(err, ErrPrincipalRequired) && ca.lenientPolicies {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("policy of entity %s: %w", validMapEntityName, err)
+		}
+		superFilters = append(superFilters, filters...)
+	}
+	return superFilters, nil
+}
@@ -0,0 +1,81 @@
+package searcher_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPoliciesFailClosed(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []searcher.Option
+		ctx     context.Context
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name:    "no principal",
+			ctx:     context.Background(),
+			wantErr: searcher.ErrPrincipalRequired,
+		},
+		{
+			name:    "principal without tenant",
+			ctx:     searcher.ContextWithPrincipal(context.Background(), searcher.Principal{UserID: "u1"}),
+			wantErr: searcher.ErrPrincipalRequired,
+		},
+		{
+			name: "principal with tenant",
+			ctx:  searcher.ContextWithPrincipal(context.Background(), searcher.Principal{TenantID: "t1"}),
+			want: bson.M{"$and": bson.A{bson.M{"tenant_id": bson.M{"$eq": "t1"}}}},
+		},
+		{
+			name: "lenient without principal",
+			opts: []searcher.Option{searcher.WithLenientPolicies()},
+			ctx:  context.Background(),
+			want: bson.M{"$and": bson.A{}},
+		},
+		{
+			name: "lenient principal without tenant",
+			opts: []searcher.Option{searcher.WithLenientPolicies()},
+			ctx:  searcher.ContextWithPrincipal(context.Background(), searcher.Principal{UserID: "u1"}),
+			want: bson.M{"$and": bson.A{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qt, err := searcher.NewQueryTranslator(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := qt.AddValidFieldsSet(models.ValidFields{
+				EntityName: "orders",
+				Fields:     map[string]models.FieldMetaData{"tenant_id": {Type: models.String}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if err := qt.AddPolicies("orders", searcher.TenantPolicy("tenant_id")); err != nil {
+				t.Fatal(err)
+			}
+
+			query, err := qt.ToMongoCtx(tt.ctx, "orders", models.Criteria{}, nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ToMongoCtx() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := query["filters"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filters = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,32 +1,52 @@
 package searcher
 
-import "github.com/solrac97gr/searcher/domain/models"
+import (
+	"fmt"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
 
 const (
-	// DefaultLogicOperator is the default logic operator
+	// DefaultLogicOperator is the default logic operator (check WithDefaultLogical)
 	DefaultLogicOperator = models.ANDLogical
-	// DefaultPaginationLimit is the default pagination limit
-	DefaultPaginationLimit uint = 50
-	// MaximumLimitOffsetSize
-	MaximumLimitOffsetSize uint = 10000
+	// DefaultPaginationLimit is the default pagination limit (check WithPaginationLimits)
+	DefaultPaginationLimit = models.DefaultPaginationLimit
+	// MaximumLimitOffsetSize is the maximum value of limit + offset (check WithPaginationLimits)
+	MaximumLimitOffsetSize = models.MaximumLimitOffsetSize
 )
 
 // PrepareCriteria is a helper function that set all the default values for a criteria independently of to what database will be executed. This will help for ensure consistency between different databases.
 func (ca *QueryTranslator) PrepareCriteria(criteria *models.Criteria) *models.Criteria {
+	return ca.prepareCriteria(criteria, ca.paginationLimits)
+}
+
+// PaginationLimits returns the pagination limits of the entity, the limits that are not set in the entity
+// are taken from the translator. Use it for validate a criteria with Criteria.ValidateWithLimits.
+func (ca *QueryTranslator) PaginationLimits(validMapEntityName string) (models.PaginationLimits, error) {
+	vf, ok := ca.fieldSets.Lookup(validMapEntityName)
+	if !ok {
+		return models.PaginationLimits{}, fmt.Errorf("%w: %w: %s", sentinels.ErrValidation, ErrFieldSetNotFound, validMapEntityName)
+	}
+	return vf.Limits.WithFallback(ca.paginationLimits), nil
+}
+
+// prepareCriteria is PrepareCriteria using the given pagination limits for the default limit.
+func (ca *QueryTranslator) prepareCriteria(criteria *models.Criteria, limits models.PaginationLimits) *models.Criteria {
 	// If the pagination Limit is set to 0 then we set the DefaultValue for the Pagination Limit
 	if criteria.Pagination.Limit == 0 {
-		criteria.Pagination.Limit = DefaultPaginationLimit
+		criteria.Pagination.Limit = limits.DefaultLimit
 	}
 	// If the number of filter is 1 or less we use the default logic Operator
 	if criteria.Query.Filters.Len() <= 1 {
-		criteria.Query.Logical = DefaultLogicOperator
+		criteria.Query.Logical = ca.defaultLogical
 	}
 
 	preparedFilters := make([]models.Filter, criteria.Query.Filters.Len())
 	// If the number of conditions inside of a filter is 1 we use the default logic Operator
 	for index, filter := range criteria.Query.Filters {
 		if filter.Conditions.Len() == 1 {
-			filter.Logical = DefaultLogicOperator
+			filter.Logical = ca.defaultLogical
 		}
 		preparedFilters[index] = filter
 	}
@@ -0,0 +1,226 @@
+package searcher
+
+import (
+	"fmt"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// queryTree builds the optimized QueryTree of a validated criteria (check models.Optimize), the super filters are
+// ANDed with the filters of the query at the root of the tree. The metadata of the fields of the super filters is
+// taken from superFilterFields, all the fields of the entity, because the super filters are not restricted by roles.
+func (ca *QueryTranslator) queryTree(vf models.ValidFields, superFilterFields map[string]models.FieldMetaData, criteria models.Criteria, superFilters []models.SuperFilter) (models.QueryTree, error) {
+	root := models.GroupNode{Logical: models.ANDLogical}
+
+	// The super filters are translated as the conditions but without validate their fields
+	for superFilterIndex, superFilter := range superFilters {
+		path := func(part string) string { return superFilterPath(superFilterIndex, part) }
+		// The metadata of the field is used for convert the values when the field is registered
+		fieldMetaData := superFilterFields[superFilter.Field]
+		fieldMetaData.Field = models.Field(superFilter.Field)
+		node, err := ca.conditionNode(fieldMetaData, superFilter.Condition(), path)
+		if err != nil {
+			return models.QueryTree{}, err
+		}
+		root.Nodes = append(root.Nodes, node)
+	}
+
+	filters := models.GroupNode{Logical: criteria.Query.Logical}
+	for filterIndex, filter := range criteria.Query.Filters {
+		conditions := models.GroupNode{Logical: filter.Logical}
+		for conditionIndex, condition := range filter.Conditions {
+			field := condition.Field.String()
+			path := func(part string) string { return conditionPath(filterIndex, conditionIndex, part) }
+
+			fieldMetaData, ok := vf.FilterField(field)
+			if !ok {
+				return models.QueryTree{}, fieldError(path("field"), models.UnknownFieldCode, field, "invalid field: %s", field)
+			}
+			if !fieldMetaData.AllowsOperator(condition.Operator) {
+				return models.QueryTree{}, fieldError(path("operator"), models.InvalidOperatorCode, condition.Operator, "invalid operator %s for field: %s", condition.Operator, field)
+			}
+			// The geo operators only can be applied to geo fields and the geo fields only support geo operators
+			if fieldMetaData.Type.Equals(models.Geo) != condition.Operator.IsGeo() {
+				return models.QueryTree{}, fieldError(path("operator"), models.InvalidOperatorCode, condition.Operator, "invalid operator %s for field: %s", condition.Operator, field)
+			}
+
+			node, err := ca.conditionNode(fieldMetaData, condition, path)
+			if err != nil {
+				return models.QueryTree{}, err
+			}
+			conditions.Nodes = append(conditions.Nodes, node)
+		}
+		filters.Nodes = append(filters.Nodes, conditions)
+	}
+	if len(filters.Nodes) > 0 {
+		root.Nodes = append(root.Nodes, filters)
+	}
+
+	sorts, err := sortNodes(criteria.Query.Sorts, vf)
+	if err != nil {
+		return models.QueryTree{}, err
+	}
+
+	return models.QueryTree{
+		Root:       models.Optimize(root),
+		Sorts:      sorts,
+		Pagination: criteria.Pagination,
+	}, nil
+}
+
+// conditionNode converts a condition over the field of the metadata to a node of the QueryTree, the invalid values
+// are reported with the path of the condition. The dates without time information are compared against the whole
+// day so the "=" and "!=" conditions over them are translated to a range and its negation.
+func (ca *QueryTranslator) conditionNode(fieldMetaData models.FieldMetaData, condition models.Condition, path func(part string) string) (models.Node, error) {
+	field := fieldMetaData.Field.String()
+
+	switch {
+	case condition.Operator.Equals(models.ExistsOperator):
+		return models.NewConditionNode(fieldMetaData, condition.Operator, condition.Value), nil
+	case condition.Operator.IsGeo():
+		geoValue, err := geoNodeValue(condition.Operator, condition.Value)
+		if err != nil {
+			return nil, fieldError(path("value"), models.InvalidValueCode, condition.Value, "invalid geo value for field: %s: %v", field, err)
+		}
+		return models.NewConditionNode(fieldMetaData, condition.Operator, geoValue), nil
+	case condition.Operator.IsList():
+		values, ok := models.ListValues(condition.Value)
+		if !ok {
+			return nil, fieldError(path("value"), models.InvalidValueCode, condition.Value, "invalid list value for field: %s", field)
+		}
+		if fieldMetaData.Type.Equals(models.Date) {
+			return ca.dateListNode(fieldMetaData, condition.Operator, values, path)
+		}
+		list := make([]interface{}, 0, len(values))
+		for index, v := range values {
+			value, err := ca.nodeValue(fieldMetaData, v, fmt.Sprintf("%s[%d]", path("value"), index))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return models.NewConditionNode(fieldMetaData, condition.Operator, list), nil
+	}
+
+	if fieldMetaData.Type.Equals(models.Date) {
+		date, err := ca.parseDate(condition.Value)
+		if err != nil {
+			return nil, fieldError(path("value"), models.TypeMismatchCode, condition.Value, "invalid date value for field: %s", field)
+		}
+		day := models.RangeNode{
+			Field:    field,
+			MetaData: fieldMetaData,
+			Lower:    &models.Bound{Value: date.Time, Inclusive: true},
+			Upper:    &models.Bound{Value: date.End},
+		}
+		if date.IsDay() && condition.Operator.Equals(models.EqualsOperator) {
+			return day, nil
+		}
+		if date.IsDay() && condition.Operator.Equals(models.NotEqualsOperator) {
+			return models.NotNode{Node: day}, nil
+		}
+		condition = dateCondition(condition, date)
+		return models.NewConditionNode(fieldMetaData, condition.Operator, condition.Value), nil
+	}
+
+	value, err := ca.nodeValue(fieldMetaData, condition.Value, path("value"))
+	if err != nil {
+		return nil, err
+	}
+	return models.NewConditionNode(fieldMetaData, condition.Operator, value), nil
+}
+
+// dateListNode converts an "in" or "not_in" condition over a Date field to a node of the QueryTree, the dates
+// without time information match the whole day so the list is translated to an "or" of the days and the "="
+// conditions of the dates with time (merged back in an "in" condition by the optimizer), and "not_in" to its negation.
+func (ca *QueryTranslator) dateListNode(fieldMetaData models.FieldMetaData, operator models.Operator, values []interface{}, path func(part string) string) (models.Node, error) {
+	field := fieldMetaData.Field.String()
+	group := models.GroupNode{Logical: models.ORLogical}
+	for index, v := range values {
+		date, err := ca.parseDate(v)
+		if err != nil {
+			return nil, fieldError(fmt.Sprintf("%s[%d]", path("value"), index), models.TypeMismatchCode, v, "invalid date value for field: %s", field)
+		}
+		if !date.IsDay() {
+			group.Nodes = append(group.Nodes, models.NewConditionNode(fieldMetaData, models.EqualsOperator, date.Time))
+			continue
+		}
+		group.Nodes = append(group.Nodes, models.RangeNode{
+			Field:    field,
+			MetaData: fieldMetaData,
+			Lower:    &models.Bound{Value: date.Time, Inclusive: true},
+			Upper:    &models.Bound{Value: date.End},
+		})
+	}
+	if operator.Equals(models.NotInOperator) {
+		return models.NotNode{Node: group}, nil
+	}
+	return group, nil
+}
+
+// parseDate parses the value of a Date field with the formatter of the translator, the models.DateValue values
+// (the whole days of a normalized criteria) are already parsed.
+func (ca *QueryTranslator) parseDate(v interface{}) (models.DateValue, error) {
+	if date, ok := v.(models.DateValue); ok {
+		return date, nil
+	}
+	return ca.dateFormatter.Parse(v)
+}
+
+// nodeValue converts a value of a condition for the QueryTree: the dates of the Date fields are parsed and the
+// strings of the ObjectID fields are checked and lowercased, they are kept as hexadecimal strings for the backends
+// without ObjectIds.
+func (ca *QueryTranslator) nodeValue(fieldMetaData models.FieldMetaData, v interface{}, path string) (interface{}, error) {
+	switch {
+	case fieldMetaData.Type.Equals(models.Date):
+		date, err := ca.parseDate(v)
+		if err != nil {
+			return nil, fieldError(path, models.TypeMismatchCode, v, "invalid date value for field: %s", fieldMetaData.Field)
+		}
+		return date.Time, nil
+	case fieldMetaData.Type.Equals(models.ObjectID):
+		if hex, ok := v.(string); ok {
+			objectID, err := primitive.ObjectIDFromHex(hex)
+			if err != nil {
+				return nil, fieldError(path, models.TypeMismatchCode, v, "invalid object id value for field: %s", fieldMetaData.Field)
+			}
+			return objectID.Hex(), nil
+		}
+	}
+	return v, nil
+}
+
+// geoNodeValue decodes the value of a geo operator to a GeoDistance, a GeoBoundingBox or a GeoPolygon.
+func geoNodeValue(operator models.Operator, v interface{}) (interface{}, error) {
+	switch operator {
+	case models.GeoDistanceOperator:
+		return models.NewGeoDistance(v)
+	case models.GeoBoundingBoxOperator:
+		return models.NewGeoBoundingBox(v)
+	case models.GeoPolygonOperator:
+		return models.NewGeoPolygon(v)
+	}
+	return nil, fmt.Errorf("invalid operator: %s is not a geo operator", operator)
+}
+
+// sortNodes resolves the fields of the sorts and checks that the point is set only for the geo fields.
+func sortNodes(sorts []models.Sort, vf models.ValidFields) ([]models.SortNode, error) {
+	nodes := make([]models.SortNode, 0, len(sorts))
+	for sortIndex, s := range sorts {
+		fieldMetaData, ok := vf.SortField(s.Field)
+		if !ok {
+			return nil, fieldError(sortPath(sortIndex, "field"), models.UnknownFieldCode, s.Field, "invalid field: %s", s.Field)
+		}
+		if fieldMetaData.Type.Equals(models.Geo) != (s.Point != nil) {
+			return nil, fieldError(sortPath(sortIndex, "point"), models.InvalidValueCode, s.Point, "invalid sort: %s the point is required only for geo fields", s.Field)
+		}
+		nodes = append(nodes, models.SortNode{
+			Field:    fieldMetaData.Field.String(),
+			MetaData: fieldMetaData,
+			Order:    s.Order,
+			Point:    s.Point,
+		})
+	}
+	return nodes, nil
+}
@@ -0,0 +1,200 @@
+package searcher_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTranslatorsRangePairs(t *testing.T) {
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "orders",
+		Fields:     map[string]models.FieldMetaData{"amount": {Type: models.Number}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	type bound struct {
+		operator models.Operator
+		value    interface{}
+	}
+	mongoAnd := func(filter bson.M) bson.M { return bson.M{"$and": bson.A{filter}} }
+	mongoRange := func(r bson.M) bson.M { return mongoAnd(bson.M{"amount": r}) }
+	mongoOr := func(a bson.M, b bson.M) bson.M {
+		return mongoAnd(bson.M{"$or": bson.A{bson.M{"amount": a}, bson.M{"amount": b}}})
+	}
+	elasticRange := func(r string) string { return `{"bool":{"must":[{"range":{"amount":` + r + `}}]}}` }
+	elasticOr := func(a string, b string) string {
+		return `{"bool":{"must":[{"bool":{"should":[{"range":{"amount":` + a + `}},{"range":{"amount":` + b + `}}]}}]}}`
+	}
+
+	tests := []struct {
+		name        string
+		logical     models.Logical
+		a, b        bound
+		wantMongo   bson.M
+		wantElastic string
+	}{
+		{
+			name:        ">= and > greater",
+			logical:     models.ANDLogical,
+			a:           bound{models.GreaterAndEqualsThan, 5},
+			b:           bound{models.GreaterThan, 7},
+			wantMongo:   mongoRange(bson.M{"$gt": 7}),
+			wantElastic: elasticRange(`{"gt":7}`),
+		},
+		{
+			name:        "> and >= greater",
+			logical:     models.ANDLogical,
+			a:           bound{models.GreaterThan, 5},
+			b:           bound{models.GreaterAndEqualsThan, 7},
+			wantMongo:   mongoRange(bson.M{"$gte": 7}),
+			wantElastic: elasticRange(`{"gte":7}`),
+		},
+		{
+			name:        "> and >= same value",
+			logical:     models.ANDLogical,
+			a:           bound{models.GreaterThan, 5},
+			b:           bound{models.GreaterAndEqualsThan, 5},
+			wantMongo:   mongoRange(bson.M{"$gt": 5}),
+			wantElastic: elasticRange(`{"gt":5}`),
+		},
+		{
+			name:        "< and <= smaller",
+			logical:     models.ANDLogical,
+			a:           bound{models.LessThan, 10},
+			b:           bound{models.LessAndEqualsThan, 7},
+			wantMongo:   mongoRange(bson.M{"$lte": 7}),
+			wantElastic: elasticRange(`{"lte":7}`),
+		},
+		{
+			name:        "<= and < same value",
+			logical:     models.ANDLogical,
+			a:           bound{models.LessAndEqualsThan, 7},
+			b:           bound{models.LessThan, 7},
+			wantMongo:   mongoRange(bson.M{"$lt": 7}),
+			wantElastic: elasticRange(`{"lt":7}`),
+		},
+		{
+			name:        "< and < smaller",
+			logical:     models.ANDLogical,
+			a:           bound{models.LessThan, 3},
+			b:           bound{models.LessThan, 10},
+			wantMongo:   mongoRange(bson.M{"$lt": 3}),
+			wantElastic: elasticRange(`{"lt":3}`),
+		},
+		{
+			name:        "> and <",
+			logical:     models.ANDLogical,
+			a:           bound{models.GreaterThan, 5},
+			b:           bound{models.LessThan, 10},
+			wantMongo:   mongoRange(bson.M{"$gt": 5, "$lt": 10}),
+			wantElastic: elasticRange(`{"gt":5,"lt":10}`),
+		},
+		{
+			name:        "<= and >=",
+			logical:     models.ANDLogical,
+			a:           bound{models.LessAndEqualsThan, 5},
+			b:           bound{models.GreaterAndEqualsThan, 5},
+			wantMongo:   mongoRange(bson.M{"$gte": 5, "$lte": 5}),
+			wantElastic: elasticRange(`{"gte":5,"lte":5}`),
+		},
+		{
+			name:        "mixed number types",
+			logical:     models.ANDLogical,
+			a:           bound{models.GreaterAndEqualsThan, 1.5},
+			b:           bound{models.GreaterThan, json.Number("2")},
+			wantMongo:   mongoRange(bson.M{"$gt": json.Number("2")}),
+			wantElastic: elasticRange(`{"gt":2}`),
+		},
+		{
+			name:        "> and < contradiction",
+			logical:     models.ANDLogical,
+			a:           bound{models.GreaterThan, 7},
+			b:           bound{models.LessThan, 3},
+			wantMongo:   mongoAnd(bson.M{"$expr": false}),
+			wantElastic: `{"bool":{"must":[{"match_none":{}}]}}`,
+		},
+		{
+			name:        ">= and < same value contradiction",
+			logical:     models.ANDLogical,
+			a:           bound{models.GreaterAndEqualsThan, 5},
+			b:           bound{models.LessThan, 5},
+			wantMongo:   mongoAnd(bson.M{"$expr": false}),
+			wantElastic: `{"bool":{"must":[{"match_none":{}}]}}`,
+		},
+		{
+			name:        ">= or >",
+			logical:     models.ORLogical,
+			a:           bound{models.GreaterAndEqualsThan, 5},
+			b:           bound{models.GreaterThan, 7},
+			wantMongo:   mongoOr(bson.M{"$gte": 5}, bson.M{"$gt": 7}),
+			wantElastic: elasticOr(`{"gte":5}`, `{"gt":7}`),
+		},
+		{
+			name:        "<= or <",
+			logical:     models.ORLogical,
+			a:           bound{models.LessAndEqualsThan, 5},
+			b:           bound{models.LessThan, 10},
+			wantMongo:   mongoOr(bson.M{"$lte": 5}, bson.M{"$lt": 10}),
+			wantElastic: elasticOr(`{"lte":5}`, `{"lt":10}`),
+		},
+		{
+			name:        "< or >",
+			logical:     models.ORLogical,
+			a:           bound{models.LessThan, 3},
+			b:           bound{models.GreaterThan, 7},
+			wantMongo:   mongoOr(bson.M{"$lt": 3}, bson.M{"$gt": 7}),
+			wantElastic: elasticOr(`{"lt":3}`, `{"gt":7}`),
+		},
+		{
+			name:        "> or <=",
+			logical:     models.ORLogical,
+			a:           bound{models.GreaterThan, 5},
+			b:           bound{models.LessAndEqualsThan, 5},
+			wantMongo:   mongoOr(bson.M{"$gt": 5}, bson.M{"$lte": 5}),
+			wantElastic: elasticOr(`{"gt":5}`, `{"lte":5}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{
+				Logical: tt.logical,
+				Conditions: models.Conditions{
+					{Field: "amount", Operator: tt.a.operator, Value: tt.a.value},
+					{Field: "amount", Operator: tt.b.operator, Value: tt.b.value},
+				},
+			}}}}
+
+			mongoQuery, err := qt.ToMongo("orders", criteria, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := mongoQuery["filters"]; !reflect.DeepEqual(got, tt.wantMongo) {
+				t.Errorf("ToMongo() filters = %#v, want %#v", got, tt.wantMongo)
+			}
+
+			elasticQuery, err := qt.ToElastic("orders", criteria, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got struct {
+				Query json.RawMessage `json:"query"`
+			}
+			if err := json.Unmarshal([]byte(elasticQuery), &got); err != nil {
+				t.Fatal(err)
+			}
+			if string(got.Query) != tt.wantElastic {
+				t.Errorf("ToElastic() query = %s, want %s", got.Query, tt.wantElastic)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package searcher
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+var (
+	// ErrFieldSetAlreadyExists is returned when a ValidFields set is registered for an entity that already has one
+	ErrFieldSetAlreadyExists = errors.New("the valid fields already set for this entity")
+	// ErrFieldSetNotFound is returned when there is no ValidFields set registered for an entity
+	ErrFieldSetNotFound = errors.New("not valid field registers")
+)
+
+// FieldSetRegistry is a concurrency-safe registry of ValidFields by entity name.
+//
+// The reads (Lookup, List and Snapshot) never block: the registry keeps an immutable
+// map that is replaced by a modified copy on every write (copy-on-write), so the field
+// sets can be hot-reloaded while other goroutines are translating queries.
+type FieldSetRegistry struct {
+	// mu serializes the writers, the readers only load the current map
+	mu   sync.Mutex
+	sets atomic.Pointer[map[string]models.ValidFields]
+}
+
+// NewFieldSetRegistry creates an empty FieldSetRegistry.
+func NewFieldSetRegistry() *FieldSetRegistry {
+	r := &FieldSetRegistry{}
+	sets := make(map[string]models.ValidFields)
+	r.sets.Store(&sets)
+	return r
+}
+
+// Register adds the ValidFields set of an entity, if the entity already has a set it returns ErrFieldSetAlreadyExists.
+func (r *FieldSetRegistry) Register(validFields models.ValidFields) error {
+	return r.write(validFields.EntityName, func(sets map[string]models.ValidFields) error {
+		if _, ok := sets[validFields.EntityName]; ok {
+			return fmt.Errorf("%w: %s", ErrFieldSetAlreadyExists, validFields.EntityName)
+		}
+		sets[validFields.EntityName] = cloneValidFields(validFields)
+		return nil
+	})
+}
+
+// Replace adds the ValidFields set of an entity replacing the previous set if it exists.
+func (r *FieldSetRegistry) Replace(validFields models.ValidFields) error {
+	return r.write(validFields.EntityName, func(sets map[string]models.ValidFields) error {
+		sets[validFields.EntityName] = cloneValidFields(validFields)
+		return nil
+	})
+}
+
+// Remove deletes the ValidFields set of an entity, if the entity doesn't have a set it returns ErrFieldSetNotFound.
+func (r *FieldSetRegistry) Remove(entityName string) error {
+	return r.write(entityName, func(sets map[string]models.ValidFields) error {
+		if _, ok := sets[entityName]; !ok {
+			return fmt.Errorf("%w: %s", ErrFieldSetNotFound, entityName)
+		}
+		delete(sets, entityName)
+		return nil
+	})
+}
+
+// Lookup returns the ValidFields set of an entity. The returned set is shared with
+// the registry and must not be modified, use Replace for change it.
+func (r *FieldSetRegistry) Lookup(entityName string) (models.ValidFields, bool) {
+	vf, ok := r.load()[entityName]
+	return vf, ok
+}
+
+// List returns the registered ValidFields sets sorted by entity name.
+func (r *FieldSetRegistry) List() []models.ValidFields {
+	sets := r.load()
+	list := make([]models.ValidFields, 0, len(sets))
+	for _, vf := range sets {
+		list = append(list, vf)
+	}
+	slices.SortFunc(list, func(a, b models.ValidFields) int {
+		return cmp.Compare(a.EntityName, b.EntityName)
+	})
+	return list
+}
+
+// Snapshot returns a copy of the registered ValidFields sets by entity name at this moment,
+// the later changes in the registry are not reflected in the snapshot.
+func (r *FieldSetRegistry) Snapshot() map[string]models.ValidFields {
+	sets := r.load()
+	snapshot := make(map[string]models.ValidFields, len(sets))
+	for name, vf := range sets {
+		snapshot[name] = vf
+	}
+	return snapshot
+}
+
+// load returns the current map of the registry, the map must not be modified.
+func (r *FieldSetRegistry) load() map[string]models.ValidFields {
+	if sets := r.sets.Load(); sets != nil {
+		return *sets
+	}
+	return nil
+}
+
+// write applies the modification over a copy of the current map and publish it if there is no error.
+func (r *FieldSetRegistry) write(entityName string, modify func(map[string]models.ValidFields) error) error {
+	if entityName == "" {
+		return fmt.Errorf("%w: the entity name of the valid fields cannot be empty", sentinels.ErrValidation)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.load()
+	sets := make(map[string]models.ValidFields, len(current)+1)
+	for name, vf := range current {
+		sets[name] = vf
+	}
+	if err := modify(sets); err != nil {
+		return err
+	}
+	r.sets.Store(&sets)
+	return nil
+}
+
+// cloneValidFields copies the fields map and the indexes so the changes of the caller over the originals don't affect the registry.
+func cloneValidFields(validFields models.ValidFields) models.ValidFields {
+	fields := make(map[string]models.FieldMetaData, len(validFields.Fields))
+	for name, fmd := range validFields.Fields {
+		fields[name] = fmd
+	}
+	validFields.Fields = fields
+	indexes := make([]models.Index, 0, len(validFields.Indexes))
+	for _, index := range validFields.Indexes {
+		index.Keys = slices.Clone(index.Keys)
+		indexes = append(indexes, index)
+	}
+	validFields.Indexes = indexes
+	return validFields
+}
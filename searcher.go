@@ -1,52 +1,179 @@
 package searcher
 
 import (
-	"errors"
+	"fmt"
 
+	"github.com/solrac97gr/searcher/date"
 	"github.com/solrac97gr/searcher/domain/models"
 	"github.com/solrac97gr/searcher/domain/ports"
-	"github.com/solrac97gr/searcher/internal/date"
-	"github.com/solrac97gr/searcher/internal/date/domain"
+	"github.com/solrac97gr/searcher/i18n"
+	"github.com/solrac97gr/searcher/internal/sentinels"
 )
 
 type QueryTranslator struct {
-	dateFormatter  domain.DateFormatter
-	ValidFieldMaps map[string]models.ValidFields
+	dateFormatter    ports.DateFormatter
+	paginationLimits models.PaginationLimits
+	complexityLimits models.ComplexityLimits
+	costWeights      models.CostWeights
+	defaultLogical   models.Logical
+	keywordSuffix    string
+	tiebreaker       *models.Sort
+	indexLint        IndexLintMode
+	lenientPolicies  bool
+	tracer           ports.Tracer
+	catalog          *i18n.Catalog
+	fieldSets        *FieldSetRegistry
+	policies         *policyRegistry
+	dialects         *dialectRegistry
 }
 
-var _ ports.QueryTranslator = &QueryTranslator{}
+var _ ports.ContextQueryTranslator = &QueryTranslator{}
 
-func NewQueryTranslator() (*QueryTranslator, error) {
-	df, err := date.NewFormatter()
-	if err != nil {
-		return nil, err
+// NewQueryTranslator creates a QueryTranslator, the default settings can be changed with the Option functions
+// (e.g. searcher.NewQueryTranslator(searcher.WithKeywordSuffix(".keyword"))).
+func NewQueryTranslator(opts ...Option) (*QueryTranslator, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
 	}
 
-	return &QueryTranslator{
-		ValidFieldMaps: make(map[string]models.ValidFields),
-		dateFormatter:  df,
-	}, nil
+	if err := o.defaultLogical.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: default logical: %v", sentinels.ErrValidation, err)
+	}
+	if o.tiebreaker != nil {
+		if err := o.tiebreaker.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: tiebreaker: %v", sentinels.ErrValidation, err)
+		}
+	}
+
+	df := o.dateFormatter
+	if df == nil {
+		var err error
+		df, err = date.NewFormatter(date.WithClock(o.clock))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ca := &QueryTranslator{
+		fieldSets:        NewFieldSetRegistry(),
+		policies:         newPolicyRegistry(),
+		dialects:         newDialectRegistry(),
+		dateFormatter:    df,
+		paginationLimits: o.paginationLimits,
+		complexityLimits: o.complexityLimits,
+		costWeights:      o.costWeights,
+		defaultLogical:   o.defaultLogical,
+		keywordSuffix:    o.keywordSuffix,
+		tiebreaker:       o.tiebreaker,
+		indexLint:        o.indexLint,
+		lenientPolicies:  o.lenientPolicies,
+		tracer:           o.tracer,
+		catalog:          o.catalog,
+	}
+
+	// The built-in dialects are registered first so they cannot be replaced
+	for _, dialect := range append([]ports.Dialect{mongoDialect{ca: ca}, elasticDialect{ca: ca}}, o.dialects...) {
+		if err := ca.dialects.register(dialect); err != nil {
+			return nil, err
+		}
+	}
+	return ca, nil
 }
 
 // AddValidFieldSet Add a new valid field set for a determined entity this only can be set one time every runtime
-// if there is another ValidFields set for an entity it will return a error
+// if there is another ValidFields set for an entity it will return a error (use ReplaceValidFieldsSet for change it)
 func (ca *QueryTranslator) AddValidFieldsSet(validFields models.ValidFields) error {
-	_, ok := ca.ValidFieldMaps[validFields.EntityName]
-	if ok {
-		return errors.New("the valid fields already set for this entity")
+	return ca.fieldSets.Register(validFields)
+}
+
+// ReplaceValidFieldsSet adds or replaces the valid field set of an entity, it's safe to call it while other
+// goroutines are translating queries (e.g. for reload the field sets from a configuration)
+func (ca *QueryTranslator) ReplaceValidFieldsSet(validFields models.ValidFields) error {
+	return ca.fieldSets.Replace(validFields)
+}
+
+// RemoveValidFieldsSet removes the valid field set of an entity, the next translations for the entity will fail
+func (ca *QueryTranslator) RemoveValidFieldsSet(validMapEntityName string) error {
+	return ca.fieldSets.Remove(validMapEntityName)
+}
+
+// ValidFieldsSet returns a copy of the valid field set registered for an entity, the changes to it don't affect
+// the translator (use ReplaceValidFieldsSet for change it)
+func (ca *QueryTranslator) ValidFieldsSet(validMapEntityName string) (models.ValidFields, bool) {
+	vf, ok := ca.fieldSets.Lookup(validMapEntityName)
+	if !ok {
+		return models.ValidFields{}, false
 	}
-	ca.ValidFieldMaps[validFields.EntityName] = validFields
-	return nil
+	return cloneValidFields(vf), true
 }
 
-func findCommonKeys(map1, map2 map[string]models.Condition) []string {
-	commonKeys := make([]string, 0)
+// VisibleFieldsSet returns the valid field set of an entity with only the fields visible for the roles
+// (check models.ValidFields.ForRoles), e.g. for list the fields that a user can filter and sort by
+func (ca *QueryTranslator) VisibleFieldsSet(validMapEntityName string, roles ...string) (models.ValidFields, bool) {
+	vf, ok := ca.fieldSets.Lookup(validMapEntityName)
+	if !ok {
+		return models.ValidFields{}, false
+	}
+	return vf.ForRoles(roles), true
+}
 
-	for key := range map1 {
-		if _, ok := map2[key]; ok {
-			commonKeys = append(commonKeys, key)
-		}
+// ValidFieldsSets returns a copy of all the valid field sets registered sorted by entity name
+func (ca *QueryTranslator) ValidFieldsSets() []models.ValidFields {
+	sets := ca.fieldSets.List()
+	for index, vf := range sets {
+		sets[index] = cloneValidFields(vf)
+	}
+	return sets
+}
+
+// FieldSets returns the registry of valid field sets used by the translator
+func (ca *QueryTranslator) FieldSets() *FieldSetRegistry {
+	return ca.fieldSets
+}
+
+// dateCondition assign the parsed date as the value of the condition, when the date represents a whole day
+// (e.g. 2024-01-31) the operators are adjusted for include or exclude the whole day:
+//
+// - "> 2024-01-31" is converted to ">= 2024-02-01T00:00:00Z"
+//
+// - "<= 2024-01-31" is converted to "< 2024-02-01T00:00:00Z"
+//
+// The "=" and "!=" operators are translated as ranges between date.Time and date.End (check conditionNode).
+func dateCondition(condition models.Condition, date models.DateValue) models.Condition {
+	condition.Value = date.Time
+	if !date.IsDay() {
+		return condition
+	}
+	if condition.Operator.Equals(models.GreaterThan) {
+		condition.Operator = models.GreaterAndEqualsThan
+		condition.Value = date.End
+	} else if condition.Operator.Equals(models.LessAndEqualsThan) {
+		condition.Operator = models.LessThan
+		condition.Value = date.End
 	}
+	return condition
+}
+
+// fieldError returns a validation error with a models.FieldError for the part of the criteria in the path
+func fieldError(path string, code models.ErrorCode, value interface{}, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %w", sentinels.ErrValidation, models.NewFieldError(path, code, value, fmt.Sprintf(format, args...)))
+}
+
+// conditionPath returns the path of a part of a condition in the criteria (e.g. query.filters[1].conditions[0].value)
+func conditionPath(filterIndex int, conditionIndex int, part string) string {
+	return fmt.Sprintf("query.filters[%d].conditions[%d].%s", filterIndex, conditionIndex, part)
+}
+
+// superFilterPath returns the path of a part of a super filter (e.g. super_filters[0].value)
+func superFilterPath(superFilterIndex int, part string) string {
+	if part == "" {
+		return fmt.Sprintf("super_filters[%d]", superFilterIndex)
+	}
+	return fmt.Sprintf("super_filters[%d].%s", superFilterIndex, part)
+}
 
-	return commonKeys
+// sortPath returns the path of a part of a sort in the criteria (e.g. query.sorts[0].field)
+func sortPath(sortIndex int, part string) string {
+	return fmt.Sprintf("query.sorts[%d].%s", sortIndex, part)
 }
@@ -0,0 +1,39 @@
+package searcher_test
+
+import (
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+func TestValidFieldsSetsReturnCopies(t *testing.T) {
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "users",
+		Fields:     map[string]models.FieldMetaData{"name": {Type: models.String}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	vf, ok := qt.ValidFieldsSet("users")
+	if !ok {
+		t.Fatal("ValidFieldsSet() not found")
+	}
+	vf.Fields["secret"] = models.FieldMetaData{Type: models.String}
+	delete(vf.Fields, "name")
+	for _, vf := range qt.ValidFieldsSets() {
+		vf.Fields["secret"] = models.FieldMetaData{Type: models.String}
+	}
+
+	vf, _ = qt.ValidFieldsSet("users")
+	if _, ok := vf.Fields["secret"]; ok {
+		t.Error("the field added to a returned set was registered")
+	}
+	if _, ok := vf.Fields["name"]; !ok {
+		t.Error("the field removed from a returned set was unregistered")
+	}
+}
@@ -0,0 +1,188 @@
+package searcher_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func newNamesTranslator(t *testing.T) *searcher.QueryTranslator {
+	t.Helper()
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fields of the super filters are not in the field set, the super filters skip its validation
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "clients",
+		Fields:     map[string]models.FieldMetaData{"name": {Type: models.String}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return qt
+}
+
+func TestSuperFilterOperators(t *testing.T) {
+	qt := newNamesTranslator(t)
+	tests := []struct {
+		name        string
+		superFilter models.SuperFilter
+		wantMongo   bson.M
+		wantElastic string
+	}{
+		{
+			name:        "equals by default",
+			superFilter: models.SuperFilter{Field: "tenant_id", Value: "t1"},
+			wantMongo:   bson.M{"tenant_id": bson.M{"$eq": "t1"}},
+			wantElastic: `{"bool":{"must":[{"term":{"tenant_id":"t1"}}]}}`,
+		},
+		{
+			name:        "in",
+			superFilter: models.SuperFilter{Field: "tenant_id", Operator: models.InOperator, Value: []string{"t1", "t2"}},
+			wantMongo:   bson.M{"tenant_id": bson.M{"$in": bson.A{"t1", "t2"}}},
+			wantElastic: `{"bool":{"must":[{"terms":{"tenant_id":["t1","t2"]}}]}}`,
+		},
+		{
+			name:        "not in",
+			superFilter: models.SuperFilter{Field: "tenant_id", Operator: models.NotInOperator, Value: []string{"t3"}},
+			wantMongo:   bson.M{"tenant_id": bson.M{"$nin": bson.A{"t3"}}},
+			wantElastic: `{"bool":{"must":[{"bool":{"must_not":[{"terms":{"tenant_id":["t3"]}}]}}]}}`,
+		},
+		{
+			name:        "not exists",
+			superFilter: models.SuperFilter{Field: "deleted_at", Operator: models.ExistsOperator, Value: false},
+			wantMongo:   bson.M{"deleted_at": bson.M{"$exists": false}},
+			wantElastic: `{"bool":{"must":[{"bool":{"must_not":[{"exists":{"field":"deleted_at"}}]}}]}}`,
+		},
+		{
+			name:        "exists",
+			superFilter: models.SuperFilter{Field: "owner_id", Operator: models.ExistsOperator, Value: true},
+			wantMongo:   bson.M{"owner_id": bson.M{"$exists": true}},
+			wantElastic: `{"bool":{"must":[{"exists":{"field":"owner_id"}}]}}`,
+		},
+		{
+			name:        "range",
+			superFilter: models.SuperFilter{Field: "age", Operator: models.GreaterAndEqualsThan, Value: 18},
+			wantMongo:   bson.M{"age": bson.M{"$gte": 18}},
+			wantElastic: `{"bool":{"must":[{"range":{"age":{"gte":18}}}]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			superFilters := []models.SuperFilter{tt.superFilter}
+
+			query, err := qt.ToMongo("clients", models.Criteria{}, superFilters)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := query["filters"], (bson.M{"$and": bson.A{tt.wantMongo}}); !reflect.DeepEqual(got, want) {
+				t.Errorf("filters = %#v, want %#v", got, want)
+			}
+
+			elasticQuery, err := qt.ToElastic("clients", models.Criteria{}, superFilters)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got struct {
+				Query json.RawMessage `json:"query"`
+			}
+			if err := json.Unmarshal([]byte(elasticQuery), &got); err != nil {
+				t.Fatal(err)
+			}
+			if string(got.Query) != tt.wantElastic {
+				t.Errorf("query = %s, want %s", got.Query, tt.wantElastic)
+			}
+		})
+	}
+}
+
+func TestSuperFiltersWithCriteria(t *testing.T) {
+	qt := newNamesTranslator(t)
+	criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+		Field: "name", Operator: models.EqualsOperator, Value: "John",
+	}}}}}}
+	superFilters := []models.SuperFilter{
+		{Field: "tenant_id", Operator: models.InOperator, Value: []string{"t1", "t2"}},
+		{Field: "deleted_at", Operator: models.ExistsOperator, Value: false},
+	}
+
+	query, err := qt.ToMongo("clients", criteria, superFilters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bson.M{"$and": bson.A{
+		bson.M{"tenant_id": bson.M{"$in": bson.A{"t1", "t2"}}},
+		bson.M{"deleted_at": bson.M{"$exists": false}},
+		bson.M{"name": bson.M{"$eq": "John"}},
+	}}
+	if got := query["filters"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("filters = %#v, want %#v", got, want)
+	}
+}
+
+func TestSuperFilterValidation(t *testing.T) {
+	qt := newNamesTranslator(t)
+	tests := []struct {
+		name        string
+		superFilter models.SuperFilter
+		wantPath    string
+		wantCode    models.ErrorCode
+	}{
+		{name: "nil equality", superFilter: models.SuperFilter{Field: "deleted_at", Value: nil}},
+		{name: "nil not equals", superFilter: models.SuperFilter{Field: "deleted_at", Operator: models.NotEqualsOperator, Value: nil}},
+		{
+			name:        "in with a scalar",
+			superFilter: models.SuperFilter{Field: "tenant_id", Operator: models.InOperator, Value: "t1"},
+			wantPath:    "super_filters[0].value",
+			wantCode:    models.InvalidValueCode,
+		},
+		{
+			name:        "in with an empty list",
+			superFilter: models.SuperFilter{Field: "tenant_id", Operator: models.InOperator, Value: []string{}},
+			wantPath:    "super_filters[0].value",
+			wantCode:    models.InvalidValueCode,
+		},
+		{
+			name:        "not in without value",
+			superFilter: models.SuperFilter{Field: "tenant_id", Operator: models.NotInOperator},
+			wantPath:    "super_filters[0].value",
+			wantCode:    models.RequiredCode,
+		},
+		{
+			name:        "exists with a string",
+			superFilter: models.SuperFilter{Field: "deleted_at", Operator: models.ExistsOperator, Value: "false"},
+			wantPath:    "super_filters[0].value",
+			wantCode:    models.InvalidValueCode,
+		},
+		{
+			name:        "unknown operator",
+			superFilter: models.SuperFilter{Field: "tenant_id", Operator: "like", Value: "t%"},
+			wantPath:    "super_filters[0].operator",
+			wantCode:    models.InvalidOperatorCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := qt.ToMongo("clients", models.Criteria{}, []models.SuperFilter{tt.superFilter})
+			if tt.wantPath == "" {
+				if err != nil {
+					t.Fatalf("ToMongo() error = %v", err)
+				}
+				return
+			}
+			fieldErrors := models.FieldErrors(err)
+			if !errors.Is(err, sentinels.ErrValidation) || len(fieldErrors) != 1 {
+				t.Fatalf("ToMongo() error = %v, want a validation error", err)
+			}
+			if fieldErrors[0].Path != tt.wantPath || fieldErrors[0].Code != tt.wantCode {
+				t.Errorf("error = %s %s, want %s %s", fieldErrors[0].Path, fieldErrors[0].Code, tt.wantPath, tt.wantCode)
+			}
+		})
+	}
+}
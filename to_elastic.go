@@ -1,11 +1,12 @@
 package searcher
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/solrac97gr/searcher/domain/models"
-	"github.com/solrac97gr/searcher/internal/sentinels"
+	"github.com/solrac97gr/searcher/domain/ports"
 )
 
 // Define the operators as constants for avoid typos and easy editing them later
@@ -15,6 +16,8 @@ const (
 	must       string = "must"
 	should     string = "should"
 	term       string = "term"
+	terms      string = "terms"
+	exists     string = "exists"
 	mustNot    string = "must_not"
 	rangeQuery string = "range"
 	gt         string = "gt"
@@ -23,6 +26,14 @@ const (
 	lte        string = "lte"
 	sort       string = "sort"
 	order      string = "order"
+	// caseInsensitive is the term query parameter for match keyword values ignoring the case
+	caseInsensitive string = "case_insensitive"
+	value           string = "value"
+	geoDistance     string = "geo_distance"
+	geoBoundingBox  string = "geo_bounding_box"
+	geoShape        string = "geo_shape"
+	geoDistanceSort string = "_geo_distance"
+	matchNone       string = "match_none"
 )
 
 // ToElastic converts criteria to an Elasticsearch query string.
@@ -31,102 +42,69 @@ const (
 //
 // The function converts the criteria into an Elasticsearch query by applying the specified filters and sorts with a set of SuperFilters in the top of the query that logically ends like (CLIENT_ID="example" AND (THE_QUERY)).
 // It checks if the entity has the permitted fields registered in the valid field map.
-// If the limit is not specified in the pagination, it defaults to 50.
+// If the limit is not specified in the pagination, it defaults to the default limit of the entity or the translator (50 if it's not configured).
 // The function builds the query using the specified filters and logical operators.
 // It handles various operators such as Equals, NotEquals, GreaterThan, LessThan, GreaterAndEqualsThan, and LessAndEqualsThan.
 // The resulting query is returned as a JSON string.
+//
+// The translation of an entity with policies fails with ErrPrincipalRequired (or the policies are skipped if they
+// are lenient), use ToElasticCtx for apply them.
 func (ca *QueryTranslator) ToElastic(validMapEntityName string, rawCriteria models.Criteria, superFilters []models.SuperFilter) (string, error) {
-	// We need to pre-process the criteria adding default values in case of some conditions are matched (check PrepareCriteria())
-	criteria := ca.PrepareCriteria(&rawCriteria)
-
-	// Initialize the query map for avoid nil queries
-	query := make(map[string]interface{})
-	// Initialize the combined query map for avoid nil combined queries
-	combinedQuery := make([]map[string]interface{}, 0)
-
-	// Check if the entity has the permitted fields register in the valid field map
-	vf, ok := ca.ValidFieldMaps[validMapEntityName]
-	if !ok {
-		return "", fmt.Errorf("invalid field: %s not valid field registers", validMapEntityName)
-	}
+	return ca.ToElasticCtx(context.Background(), validMapEntityName, rawCriteria, superFilters)
+}
 
-	// build the sorts using the helper function BuildSorts
-	sorts, err := BuildSorts(criteria.Query.Sorts, vf)
+// ToElasticCtx converts the criteria to an Elasticsearch query string adding the filters of the policies of the
+// entity for the principal of the context (check AddPolicies and ContextWithPrincipal) to the super filters, the
+// fields that the roles of the principal cannot see are rejected as unknown (check models.FieldMetaData.Roles).
+//
+// The translation is traced and its validation errors are localized as in ToMongoCtx.
+func (ca *QueryTranslator) ToElasticCtx(ctx context.Context, validMapEntityName string, rawCriteria models.Criteria, superFilters []models.SuperFilter) (string, error) {
+	query, err := ca.Translate(ctx, ElasticDialect, validMapEntityName, rawCriteria, superFilters...)
 	if err != nil {
 		return "", err
 	}
+	return query.(string), nil
+}
+
+// elasticDialect is the built-in Dialect for Elasticsearch, its queries are JSON strings.
+type elasticDialect struct {
+	ca *QueryTranslator
+}
+
+// Name returns ElasticDialect.
+func (d elasticDialect) Name() string {
+	return ElasticDialect
+}
+
+// Translate converts the criteria of the request to an Elasticsearch query string.
+func (d elasticDialect) Translate(_ context.Context, request ports.TranslationRequest) (interface{}, error) {
+	return d.ca.elasticQuery(request)
+}
+
+// elasticQuery converts the QueryTree of a validated criteria to an Elasticsearch query string.
+func (ca *QueryTranslator) elasticQuery(request ports.TranslationRequest) (string, error) {
+	tree := request.Tree
+
+	// Initialize the query map for avoid nil queries
+	query := make(map[string]interface{})
+
 	// Assign the sort
-	query[sort] = sorts
-
-	// Iterate through the Filters inside of the Query
-	for _, filter := range criteria.Query.Filters {
-		// Initialize the conditions map for avoid nil condition map
-		conditions := make([]map[string]interface{}, 0)
-		// Initialize the GreaterThan map for avoid nil GreaterThan map
-		gtMaps := make(map[string]models.Condition)
-		// Initialize the LessThan map for avoid nil LessThan map
-		ltMaps := make(map[string]models.Condition)
-
-		for _, condition := range filter.Conditions {
-			// Convert the condition Field to string for easier comparison
-			field := condition.Field.String()
-
-			// Check if the field is a valid field for search query
-			fieldMetaData, ok := vf.Fields[field]
-			if !ok {
-				return "", fmt.Errorf("%w:invalid field: %s", sentinels.ErrValidation, field)
-			}
-			// If the field is marked as analyzed field we add ".raw" to the field name this is because the raw value of the field is storage in the key with name raw
-			if fieldMetaData.IsAnalyzed {
-				field = field + ".raw"
-			}
-			// Check if the Field is type Date for convert it from ISO Date string to Unix timestamp
-			if fieldMetaData.Type.Equals(models.Date) {
-				// Convert the Value (interface{}) to a string
-				date, ok := condition.Value.(string)
-				if !ok {
-					return "", fmt.Errorf("%w:invalid date field: %s", sentinels.ErrValidation, field)
-				}
-				// Perform the conversion from ISO to Unix timestamp
-				newDate, err := ca.dateFormatter.FromISO8601String(date)
-				if err != nil {
-					return "", fmt.Errorf("%w:invalid date field: %s", sentinels.ErrValidation, field)
-				}
-				// Assign the new processed value
-				condition.Value = newDate
-			}
-			// Assign the operator from the condition for the switch
-			operator := condition.Operator
-
-			// Using a switch case we append the respective conditions to their respective ElasticSearch formatted conditions except for the >,>= and <,<= conditions that are compiled in a map for another processing (determinate if ranges exists) step before to be formatted as ElasticSearch format
-			switch operator {
-			case models.EqualsOperator:
-				conditions = append(conditions, createEqualsCondition(field, condition.Value))
-			case models.NotEqualsOperator:
-				conditions = append(conditions, createNotEqualsCondition(field, condition.Value))
-			case models.GreaterThan:
-				gtMaps[field] = condition
-			case models.LessThan:
-				ltMaps[field] = condition
-			case models.GreaterAndEqualsThan:
-				gtMaps[field] = condition
-			case models.LessAndEqualsThan:
-				ltMaps[field] = condition
-			}
-		}
+	query[sort] = ca.elasticSorts(tree.Sorts)
 
-		// Process the conditions for group the conditions that are has a common field in range conditions
-		processRangeConditions(&conditions, &gtMaps, &ltMaps)
-		// Add the conditions that are already processed to the combined query
-		addFilterConditions(&combinedQuery, filter.Logical, conditions)
+	// The nodes of the root are always ANDed at the top level
+	conditions := make([]map[string]interface{}, 0)
+	for _, node := range rootNodes(tree.Root) {
+		conditions = append(conditions, ca.elasticFilter(node))
+	}
+	query[queryKey] = map[string]interface{}{
+		boolQuery: map[string]interface{}{
+			must: conditions,
+		},
 	}
-
-	// Build the query after all conditions have been processed into the Elasticsearch format
-	buildQuery(superFilters, &query, &combinedQuery, criteria.Query.Logical)
 
 	// Set the pagination parameters for the query
-	query["size"] = criteria.Pagination.Limit
-	query["from"] = criteria.Pagination.Offset
+	query["size"] = tree.Pagination.Limit
+	query["from"] = tree.Pagination.Offset
 
 	// marshal the query for after converting to a string
 	jsonQuery, err := json.Marshal(query)
@@ -137,42 +115,147 @@ func (ca *QueryTranslator) ToElastic(validMapEntityName string, rawCriteria mode
 	return string(jsonQuery), nil
 }
 
+// elasticFilter translates a node of the QueryTree to the Elasticsearch format:
+//
+// - AND = MUST
+//
+// - OR = SHOULD
+func (ca *QueryTranslator) elasticFilter(node models.Node) map[string]interface{} {
+	switch n := node.(type) {
+	case models.GroupNode:
+		occurrence := must
+		if n.Logical.Equals(models.ORLogical) {
+			occurrence = should
+		}
+		conditions := make([]map[string]interface{}, 0, len(n.Nodes))
+		for _, child := range n.Nodes {
+			conditions = append(conditions, ca.elasticFilter(child))
+		}
+		return map[string]interface{}{
+			boolQuery: map[string]interface{}{
+				occurrence: conditions,
+			},
+		}
+	case models.ConditionNode:
+		return ca.elasticCondition(n)
+	case models.RangeNode:
+		return createRangeCondition(ca.elasticField(n.MetaData), n.Lower, n.Upper)
+	case models.NotNode:
+		return createMustNotCondition(ca.elasticFilter(n.Node))
+	case models.MatchNoneNode:
+		return map[string]interface{}{
+			matchNone: map[string]interface{}{},
+		}
+	}
+	return map[string]interface{}{}
+}
+
+// elasticField returns the field used in the term and range queries, if the field is marked as analyzed field we
+// add the keyword suffix (".raw" by default) to the field name this is because the raw value of the field is
+// storage in the key with name raw
+func (ca *QueryTranslator) elasticField(fieldMetaData models.FieldMetaData) string {
+	if fieldMetaData.IsAnalyzed {
+		return fieldMetaData.Field.String() + ca.keywordSuffix
+	}
+	return fieldMetaData.Field.String()
+}
+
+// elasticCondition translates a condition of the QueryTree to the Elasticsearch format.
+func (ca *QueryTranslator) elasticCondition(condition models.ConditionNode) map[string]interface{} {
+	field := ca.elasticField(condition.MetaData)
+	isCaseInsensitive := condition.MetaData.IsCaseInsensitive
+
+	switch condition.Operator {
+	case models.EqualsOperator:
+		return createEqualsCondition(field, termValue(condition.Value, isCaseInsensitive))
+	case models.NotEqualsOperator:
+		return createNotEqualsCondition(field, termValue(condition.Value, isCaseInsensitive))
+	case models.InOperator, models.NotInOperator:
+		values, _ := models.ListValues(condition.Value)
+		inCondition := createInCondition(field, values, isCaseInsensitive)
+		if condition.Operator.Equals(models.NotInOperator) {
+			inCondition = createMustNotCondition(inCondition)
+		}
+		return inCondition
+	case models.ExistsOperator:
+		// The exists query is applied over the field itself, the keyword suffix is not needed
+		existsCondition := map[string]interface{}{
+			exists: map[string]interface{}{
+				"field": condition.Field,
+			},
+		}
+		if present, _ := condition.Value.(bool); !present {
+			existsCondition = createMustNotCondition(existsCondition)
+		}
+		return existsCondition
+	}
+	return createGeoCondition(field, condition.Value)
+}
+
 // BuildSorts builds the sorts for the given query and validate if the sorting fields are valid
+// using the default keyword suffix and tiebreaker (check WithKeywordSuffix and WithTiebreaker)
 func BuildSorts(sorts []models.Sort, vf models.ValidFields) ([]map[string]interface{}, error) {
+	o := defaultOptions()
+	ca := &QueryTranslator{keywordSuffix: o.keywordSuffix, tiebreaker: o.tiebreaker}
+	nodes, err := sortNodes(sorts, vf)
+	if err != nil {
+		return nil, err
+	}
+	return ca.elasticSorts(nodes), nil
+}
+
+// elasticSorts builds the sorts of the QueryTree in the Elasticsearch format
+func (ca *QueryTranslator) elasticSorts(sorts []models.SortNode) []map[string]interface{} {
 	buildedSorts := make([]map[string]interface{}, 0)
 
 	for _, srt := range sorts {
-		fieldName := srt.Field
-		fmd, ok := vf.Fields[fieldName]
-		if !ok {
-			return nil, fmt.Errorf("%w: %s not valid field registers", sentinels.ErrValidation, fieldName)
+		// The geo fields are sorted by the distance to the point of the sort
+		if srt.Point != nil {
+			buildedSorts = append(buildedSorts, map[string]interface{}{
+				geoDistanceSort: map[string]interface{}{
+					srt.Field: srt.Point,
+					order:     srt.Order.String(),
+					"unit":    models.Meters,
+				},
+			})
+			continue
 		}
-		// If the field is analyzed we add the .raw in the name of the field
+		// If the field is analyzed we add the keyword suffix (.raw by default) in the name of the field
 		// these is the right way for perform and order in analyzed fields in
 		// elasticsearch
-		if fmd.IsAnalyzed {
-			fieldName = fmt.Sprintf("%s.raw", fieldName)
-		}
-
 		nSort := map[string]interface{}{
-			string(fieldName): map[string]string{
+			ca.elasticField(srt.MetaData): map[string]string{
 				order: srt.Order.String(),
 			},
 		}
 		buildedSorts = append(buildedSorts, nSort)
 	}
 
-	// Add the default sort field (bayonet_tracking_id)
+	// Add the tiebreaker sort field (bayonet_tracking_id by default)
 	// we add direct in the buildedSorts for skip the validation of the fields
 	// this will permit the field be ordered independently if can be queried
-	defaultSort := map[string]interface{}{
-		"bayonet_tracking_id": map[string]string{
-			order: "asc",
-		},
+	if ca.tiebreaker != nil {
+		defaultSort := map[string]interface{}{
+			ca.tiebreaker.Field: map[string]string{
+				order: ca.tiebreaker.Order.String(),
+			},
+		}
+		buildedSorts = append(buildedSorts, defaultSort)
 	}
-	buildedSorts = append(buildedSorts, defaultSort)
 
-	return buildedSorts, nil
+	return buildedSorts
+}
+
+// termValue helper function for build the value of a term query, when the field is case insensitive
+// and the value is a string the value is expanded to the long term syntax with "case_insensitive" enabled
+func termValue(v interface{}, isCaseInsensitive bool) interface{} {
+	if _, ok := v.(string); !ok || !isCaseInsensitive {
+		return v
+	}
+	return map[string]interface{}{
+		value:           v,
+		caseInsensitive: true,
+	}
 }
 
 // createEqualsCondition helper function for create a equal condition for Elasticsearch
@@ -199,172 +282,92 @@ func createNotEqualsCondition(field string, value interface{}) map[string]interf
 	}
 }
 
-// processRangeConditions take the conditions and the maps of GreaterThan and LessThan conditions for create the ElasticSearch formatted range conditions depending of their type of logical operator and if exists common fields
-func processRangeConditions(conditions *[]map[string]interface{}, gtMaps *map[string]models.Condition, ltMaps *map[string]models.Condition) {
-	commonKeys := findCommonKeys(*gtMaps, *ltMaps)
-
-	for _, key := range commonKeys {
-		*conditions = append(*conditions, createRangeCondition(
-			key,
-			(*gtMaps)[key].Operator,
-			(*ltMaps)[key].Operator,
-
-			(*gtMaps)[key].Value,
-			(*ltMaps)[key].Value,
-		),
-		)
-		delete(*gtMaps, key)
-		delete(*ltMaps, key)
-	}
-
-	for key := range *gtMaps {
-		if (*gtMaps)[key].Operator.Equals(models.GreaterAndEqualsThan) {
-			*conditions = append(*conditions, createGreaterAndEqualsThanCondition(key, (*gtMaps)[key].Value))
-		}
-		*conditions = append(*conditions, createGreaterThanCondition(key, (*gtMaps)[key].Value))
-	}
-
-	for key := range *ltMaps {
-		if (*ltMaps)[key].Operator.Equals(models.LessAndEqualsThan) {
-			*conditions = append(*conditions, createLessAndEqualsThanCondition(key, (*ltMaps)[key].Value))
-		}
-		*conditions = append(*conditions, createLessThanCondition(key, (*ltMaps)[key].Value))
-	}
-}
-
-// createRangeCondition helper function for creating a range condition for ElasticSearch
-func createRangeCondition(key string, gtOperator models.Operator, ltOperator models.Operator, gtValue interface{}, ltValue interface{}) map[string]interface{} {
-	gtOp := gt
-	ltOp := lt
-
-	if gtOperator.Equals(models.GreaterAndEqualsThan) {
-		gtOp = gte
-	}
-
-	if ltOperator.Equals(models.LessAndEqualsThan) {
-		ltOp = lte
-	}
-
-	return map[string]interface{}{
-		rangeQuery: map[string]interface{}{
-			key: map[string]interface{}{
-				gtOp: gtValue,
-				ltOp: ltValue,
+// createInCondition helper function for create a terms condition for Elasticsearch, the terms query cannot ignore the case
+// so for case insensitive fields it's a should of term conditions
+func createInCondition(field string, values []interface{}, isCaseInsensitive bool) map[string]interface{} {
+	if !isCaseInsensitive {
+		return map[string]interface{}{
+			terms: map[string]interface{}{
+				field: values,
 			},
-		},
+		}
 	}
-}
-
-// createGreaterThanCondition helper function for create a formatted Greater Than condition for ElasticSearch
-func createGreaterThanCondition(key string, value interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		rangeQuery: map[string]interface{}{
-			key: map[string]interface{}{
-				gt: value,
-			},
-		},
+	termConditions := make([]map[string]interface{}, 0, len(values))
+	for _, v := range values {
+		termConditions = append(termConditions, createEqualsCondition(field, termValue(v, true)))
 	}
-}
-
-// createLessThanCondition helper function for create a formatted Less Than condition for ElasticSearch
-func createLessThanCondition(key string, value interface{}) map[string]interface{} {
 	return map[string]interface{}{
-		rangeQuery: map[string]interface{}{
-			key: map[string]interface{}{
-				lt: value,
-			},
+		boolQuery: map[string]interface{}{
+			should: termConditions,
 		},
 	}
 }
 
-// createGreaterAndEqualThanCondition helper function for create a formatted Greater and Equal condition for ElasticSearch
-func createGreaterAndEqualsThanCondition(key string, value interface{}) map[string]interface{} {
+// createMustNotCondition helper function for negate a condition for Elasticsearch
+func createMustNotCondition(condition map[string]interface{}) map[string]interface{} {
 	return map[string]interface{}{
-		rangeQuery: map[string]interface{}{
-			key: map[string]interface{}{
-				gte: value,
-			},
+		boolQuery: map[string]interface{}{
+			mustNot: []map[string]interface{}{condition},
 		},
 	}
 }
 
-// createLessAndEqualsThanCondition helper function for create a formatted Less and equal condition for Elasticsearch
-func createLessAndEqualsThanCondition(key string, value interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		rangeQuery: map[string]interface{}{
-			key: map[string]interface{}{
-				lte: value,
+// createGeoCondition helper function for create a geo_distance, geo_bounding_box or geo_shape condition for Elasticsearch,
+// the polygons use geo_shape because geo_polygon is deprecated since Elasticsearch 7.12 and removed in 8
+// from a decoded geo value
+func createGeoCondition(field string, v interface{}) map[string]interface{} {
+	switch value := v.(type) {
+	case models.GeoDistance:
+		return map[string]interface{}{
+			geoDistance: map[string]interface{}{
+				"distance": fmt.Sprintf("%vm", value.Meters()),
+				field:      value.Point,
 			},
-		},
-	}
-}
-
-// addFilterConditions add the conditions to the filter depending on the logical operator set for that filter
-//
-// - AND = MUST
-//
-// - OR = SHOULD
-func addFilterConditions(combinedQuery *[]map[string]interface{}, logical models.Logical, conditions []map[string]interface{}) {
-	if logical.Equals(models.ORLogical) && len(conditions) > 0 {
-		*combinedQuery = append(*combinedQuery, map[string]interface{}{
-			boolQuery: map[string]interface{}{
-				should: conditions,
+		}
+	case models.GeoBoundingBox:
+		return map[string]interface{}{
+			geoBoundingBox: map[string]interface{}{
+				field: value,
 			},
-		})
-	} else if logical.Equals(models.ANDLogical) && len(conditions) > 0 {
-		*combinedQuery = append(*combinedQuery, map[string]interface{}{
-			boolQuery: map[string]interface{}{
-				must: conditions,
+		}
+	case models.GeoPolygon:
+		coordinates := make([][]float64, 0, len(value.Points)+1)
+		for _, p := range value.Closed() {
+			coordinates = append(coordinates, []float64{p.Lon, p.Lat})
+		}
+		return map[string]interface{}{
+			geoShape: map[string]interface{}{
+				field: map[string]interface{}{
+					"shape":    map[string]interface{}{"type": "polygon", "coordinates": [][][]float64{coordinates}},
+					"relation": "within",
+				},
 			},
-		})
+		}
 	}
+	return map[string]interface{}{}
 }
 
-// buildQuery a helper function to build a query adding the top level operator and the super filters to the conditions
-//
-// - AND = MUST
-//
-// - OR = SHOULD
-//
-// The super filters it adds a top level operator extra with always a MUST condition where the super filters are set
-func buildQuery(superFilters []models.SuperFilter, query *map[string]interface{}, combinedQuery *[]map[string]interface{}, logical models.Logical) {
-
-	// We build the super filters to the Query
-	queryWithSuperFilters := []map[string]interface{}{}
-	for _, superFilter := range superFilters {
-		queryWithSuperFilters = append(queryWithSuperFilters, map[string]interface{}{
-			"term": map[string]interface{}{
-				superFilter.Field: superFilter.Value,
-			},
-		})
-	}
-
-	if logical.Equals(models.ANDLogical) {
-		if len(*combinedQuery) > 0 {
-			queryWithSuperFilters = append(queryWithSuperFilters, map[string]interface{}{
-				boolQuery: map[string]interface{}{
-					must: *combinedQuery,
-				},
-			})
+// createRangeCondition helper function for creating a range condition for ElasticSearch with the bounds that are set
+func createRangeCondition(key string, lower *models.Bound, upper *models.Bound) map[string]interface{} {
+	bounds := make(map[string]interface{})
+	if lower != nil {
+		gtOp := gt
+		if lower.Inclusive {
+			gtOp = gte
 		}
-		(*query)[queryKey] = map[string]interface{}{
-			boolQuery: map[string]interface{}{
-				must: queryWithSuperFilters,
-			},
-		}
-	} else if logical.Equals(models.ORLogical) {
-		if len(*combinedQuery) > 0 {
-			queryWithSuperFilters = append(queryWithSuperFilters, map[string]interface{}{
-				boolQuery: map[string]interface{}{
-					should: *combinedQuery,
-				},
-			})
-		}
-		(*query)[queryKey] = map[string]interface{}{
-			boolQuery: map[string]interface{}{
-				must: queryWithSuperFilters,
-			},
+		bounds[gtOp] = lower.Value
+	}
+	if upper != nil {
+		ltOp := lt
+		if upper.Inclusive {
+			ltOp = lte
 		}
+		bounds[ltOp] = upper.Value
 	}
 
+	return map[string]interface{}{
+		rangeQuery: map[string]interface{}{
+			key: bounds,
+		},
+	}
 }
@@ -0,0 +1,54 @@
+package searcher_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/solrac97gr/searcher/domain/models"
+)
+
+func TestToElasticGeoConditions(t *testing.T) {
+	qt := newGeoTranslator(t)
+	tests := []struct {
+		name     string
+		operator models.Operator
+		value    interface{}
+		want     string
+	}{
+		{
+			name:     "polygon uses geo_shape",
+			operator: models.GeoPolygonOperator,
+			value:    models.GeoPolygon{Points: []models.GeoPoint{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 10}, {Lat: 10, Lon: 10}}},
+			want:     `{"bool":{"must":[{"geo_shape":{"location":{"relation":"within","shape":{"coordinates":[[[0,0],[10,0],[10,10],[0,0]]],"type":"polygon"}}}}]}}`,
+		},
+		{
+			name:     "bounding box",
+			operator: models.GeoBoundingBoxOperator,
+			value:    models.GeoBoundingBox{TopLeft: models.GeoPoint{Lat: 10, Lon: -20}, BottomRight: models.GeoPoint{Lat: -10, Lon: 20}},
+			want:     `{"bool":{"must":[{"geo_bounding_box":{"location":{"top_left":{"lat":10,"lon":-20},"bottom_right":{"lat":-10,"lon":20}}}}]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+				Field:    "location",
+				Operator: tt.operator,
+				Value:    tt.value,
+			}}}}}}
+
+			query, err := qt.ToElastic("places", criteria, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got struct {
+				Query json.RawMessage `json:"query"`
+			}
+			if err := json.Unmarshal([]byte(query), &got); err != nil {
+				t.Fatal(err)
+			}
+			if string(got.Query) != tt.want {
+				t.Errorf("query = %s, want %s", got.Query, tt.want)
+			}
+		})
+	}
+}
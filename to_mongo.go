@@ -1,93 +1,90 @@
 package searcher
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 
 	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/domain/ports"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/solrac97gr/searcher/internal/sentinels"
 )
 
+// ToMongo is ToMongoCtx without principal, the translation of an entity with policies fails with
+// ErrPrincipalRequired (or the policies are skipped if they are lenient).
 func (ca *QueryTranslator) ToMongo(validMapEntityName string, rawCriteria models.Criteria, superFilters []models.SuperFilter) (models.MongoQuery, error) {
-	// We need to pre-process the criteria adding default values in case of some conditions are matched (check PrepareCriteria())
-	c := ca.PrepareCriteria(&rawCriteria)
+	return ca.ToMongoCtx(context.Background(), validMapEntityName, rawCriteria, superFilters)
+}
 
-	query := make(map[string]interface{})
+// ToMongoCtx converts the criteria to a MongoDB query adding the filters of the policies of the entity for the
+// principal of the context (check AddPolicies and ContextWithPrincipal) to the super filters, the fields that the
+// roles of the principal cannot see are rejected as unknown (check models.FieldMetaData.Roles).
+//
+// The translation is traced with the Tracer of the translator (check WithTracer) and the validation errors are
+// localized to the locale of the context when the translator has a Catalog (check WithCatalog).
+func (ca *QueryTranslator) ToMongoCtx(ctx context.Context, validMapEntityName string, rawCriteria models.Criteria, superFilters []models.SuperFilter) (models.MongoQuery, error) {
+	query, err := ca.Translate(ctx, MongoDialect, validMapEntityName, rawCriteria, superFilters...)
+	if err != nil {
+		return nil, err
+	}
+	return query.(models.MongoQuery), nil
+}
 
-	// Add pagination to the query
-	query["limit"] = c.Pagination.Limit
-	query["offset"] = c.Pagination.Offset
+// mongoDialect is the built-in Dialect for MongoDB, its queries are models.MongoQuery values.
+type mongoDialect struct {
+	ca *QueryTranslator
+}
 
-	// Check if the valid fields are correctly registered
-	vf, ok := ca.ValidFieldMaps[validMapEntityName]
-	if !ok {
-		return nil, fmt.Errorf("%w: %s not valid field registers", sentinels.ErrValidation, validMapEntityName)
-	}
+// Name returns MongoDialect.
+func (d mongoDialect) Name() string {
+	return MongoDialect
+}
 
-	// Add filters to the query
-	filters := bson.A{}
-	for _, filter := range c.Query.Filters {
-		conditions := bson.A{}
-		for _, condition := range filter.Conditions {
-			field := condition.Field.String()
-
-			fieldMetaData, ok := vf.Fields[field]
-			if !ok {
-				return nil, fmt.Errorf("%w:invalid field: %s", sentinels.ErrValidation, field)
-			}
-			if fieldMetaData.Type.Equals(models.Date) {
-				date, ok := condition.Value.(string)
-				if !ok {
-					return nil, fmt.Errorf("%w:invalid field: %s", sentinels.ErrValidation, field)
-				}
-				nDate, err := ca.dateFormatter.FromISO8601String(date)
-				if err != nil {
-					return nil, fmt.Errorf("%w:invalid field: %s", sentinels.ErrValidation, field)
-				}
-				condition.Value = nDate
-			}
+// Translate converts the criteria of the request to a models.MongoQuery.
+func (d mongoDialect) Translate(_ context.Context, request ports.TranslationRequest) (interface{}, error) {
+	return d.ca.mongoQuery(request)
+}
 
-			operator := condition.Operator
-			if operator.Equals(models.NotEqualsOperator) {
-				operator = "$ne"
-			} else if operator.Equals(models.EqualsOperator) {
-				operator = "$eq"
-			} else if operator.Equals(models.GreaterThan) {
-				operator = "$gt"
-			} else if operator.Equals(models.LessThan) {
-				operator = "$lt"
-			} else if operator.Equals(models.LessAndEqualsThan) {
-				operator = "$lte"
-			} else if operator.Equals(models.GreaterAndEqualsThan) {
-				operator = "$gte"
-			}
-			conditions = append(conditions, bson.M{string(condition.Field): bson.M{operator.String(): condition.Value}})
-		}
-		filters = append(filters, bson.M{"$" + string(filter.Logical): conditions})
-	}
-	query["filters"] = bson.M{
-		"$and": func() bson.A {
-			// We add the super filters to the Top Level Query.
-			sf := bson.A{}
-			for _, superFilter := range superFilters {
-				sf = append(sf, bson.M{
-					superFilter.Field: superFilter.Value,
-				})
-			}
-			return sf
-		}(),
+// mongoQuery converts the QueryTree of a validated criteria to a MongoDB query, the criteria is used for the index lint.
+func (ca *QueryTranslator) mongoQuery(request ports.TranslationRequest) (models.MongoQuery, error) {
+	vf, c, superFilters := request.Fields, &request.Criteria, request.SuperFilters
+
+	// Check if the filters and the sorts can use the indexes of the entity
+	warnings := ca.lintIndexes(vf, *c, superFilters)
+	if len(warnings) > 0 && ca.indexLint == IndexLintReject {
+		return nil, fmt.Errorf("%w: %w", sentinels.ErrValidation, warningErrors(warnings))
 	}
-	if len(filters) > 0 {
-		query["filters"].(bson.M)["$and"] = append(query["filters"].(bson.M)["$and"].(bson.A), bson.M{"$" + c.Query.Logical.String(): filters})
+
+	tree := request.Tree
+	query := make(map[string]interface{})
+
+	// Add pagination to the query
+	query["limit"] = tree.Pagination.Limit
+	query["offset"] = tree.Pagination.Offset
+
+	// Add filters to the query, the nodes of the root are always ANDed at the top level
+	filters := bson.A{}
+	for _, node := range rootNodes(tree.Root) {
+		filters = append(filters, mongoFilter(node))
 	}
+	query["filters"] = bson.M{"$and": filters}
 
 	// Add sort to the query
 	sort := bson.M{}
-	for _, s := range c.Query.Sorts {
-		_, ok := vf.Fields[s.Field]
-		if !ok {
-			return nil, fmt.Errorf("%w:invalid field: %s", sentinels.ErrValidation, s.Field)
+	for sortIndex, s := range tree.Sorts {
+		// Mongo can't sort by distance in the sort document, the $nearSphere operator returns the documents
+		// sorted from the nearest to the farthest so we add it to the top level filters
+		if s.Point != nil {
+			if s.Order.Equals(models.DESCOrder) {
+				return nil, fieldError(sortPath(sortIndex, "order"), models.InvalidValueCode, s.Order, "invalid sort: %s only can be sorted by distance in asc order", s.Field)
+			}
+			query["filters"].(bson.M)["$and"] = append(query["filters"].(bson.M)["$and"].(bson.A), bson.M{
+				s.Field: bson.M{"$nearSphere": bson.M{"$geometry": geoJSONPoint(*s.Point)}},
+			})
+			continue
 		}
 
 		order := 1
@@ -98,6 +95,166 @@ func (ca *QueryTranslator) ToMongo(validMapEntityName string, rawCriteria models
 	}
 
 	query["sorts"] = sort
+	if len(warnings) > 0 {
+		query["warnings"] = warnings
+	}
 
 	return query, nil
 }
+
+// rootNodes returns the nodes ANDed at the top level of the query: the nodes of the root when it's an "and" group
+// (none for an empty group) or the root itself.
+func rootNodes(root models.Node) []models.Node {
+	if group, ok := root.(models.GroupNode); ok && (group.Logical.Equals(models.ANDLogical) || len(group.Nodes) == 0) {
+		return group.Nodes
+	}
+	return []models.Node{root}
+}
+
+// mongoFilter translates a node of the QueryTree to a MongoDB expression.
+func mongoFilter(node models.Node) bson.M {
+	switch n := node.(type) {
+	case models.GroupNode:
+		nodes := bson.A{}
+		for _, child := range n.Nodes {
+			nodes = append(nodes, mongoFilter(child))
+		}
+		return bson.M{"$" + n.Logical.String(): nodes}
+	case models.ConditionNode:
+		return mongoCondition(n)
+	case models.RangeNode:
+		return bson.M{n.Field: mongoRange(n)}
+	case models.NotNode:
+		// $not only can negate the operator expressions of a field, the other nodes are negated with $nor
+		if r, ok := n.Node.(models.RangeNode); ok {
+			return bson.M{r.Field: bson.M{"$not": mongoRange(r)}}
+		}
+		return bson.M{"$nor": bson.A{mongoFilter(n.Node)}}
+	case models.MatchNoneNode:
+		return bson.M{"$expr": false}
+	}
+	return bson.M{}
+}
+
+// mongoRange translates the bounds of a range to the $gt, $gte, $lt and $lte operators.
+func mongoRange(r models.RangeNode) bson.M {
+	expression := bson.M{}
+	if r.Lower != nil {
+		operator := "$gt"
+		if r.Lower.Inclusive {
+			operator = "$gte"
+		}
+		expression[operator] = mongoValue(r.MetaData, r.Lower.Value)
+	}
+	if r.Upper != nil {
+		operator := "$lt"
+		if r.Upper.Inclusive {
+			operator = "$lte"
+		}
+		expression[operator] = mongoValue(r.MetaData, r.Upper.Value)
+	}
+	return expression
+}
+
+// mongoCondition translates a condition of the QueryTree to a MongoDB expression.
+func mongoCondition(condition models.ConditionNode) bson.M {
+	field := condition.Field
+	fieldMetaData := condition.MetaData
+
+	switch {
+	case condition.Operator.Equals(models.ExistsOperator):
+		return bson.M{field: bson.M{"$exists": condition.Value}}
+	case condition.Operator.IsGeo():
+		return bson.M{field: createMongoGeoCondition(condition.Value)}
+	case condition.Operator.IsList():
+		values, _ := models.ListValues(condition.Value)
+		list := make(bson.A, 0, len(values))
+		for _, v := range values {
+			value := mongoValue(fieldMetaData, v)
+			// The regexes are accepted by $in and $nin so the case insensitive fields are matched as in the equality
+			if s, ok := value.(string); ok && fieldMetaData.IsCaseInsensitive {
+				value = caseInsensitiveRegex(s)
+			}
+			list = append(list, value)
+		}
+		operator := "$in"
+		if condition.Operator.Equals(models.NotInOperator) {
+			operator = "$nin"
+		}
+		return bson.M{field: bson.M{operator: list}}
+	}
+
+	operator := "$eq"
+	if condition.Operator.Equals(models.NotEqualsOperator) {
+		operator = "$ne"
+	}
+	value := mongoValue(fieldMetaData, condition.Value)
+	// For case insensitive fields the equality is performed with an anchored regex with the "i" option
+	if s, ok := value.(string); ok && fieldMetaData.IsCaseInsensitive {
+		if operator == "$eq" {
+			return bson.M{field: bson.M{"$regex": caseInsensitiveRegex(s)}}
+		}
+		return bson.M{field: bson.M{"$not": caseInsensitiveRegex(s)}}
+	}
+	return bson.M{field: bson.M{operator: value}}
+}
+
+// mongoValue converts a value of the QueryTree to the type stored in MongoDB, the hexadecimal strings of the
+// ObjectID fields are converted for match the stored ObjectIds (they are already checked by the tree).
+func mongoValue(fieldMetaData models.FieldMetaData, v interface{}) interface{} {
+	if hex, ok := v.(string); ok && fieldMetaData.Type.Equals(models.ObjectID) {
+		if objectID, err := primitive.ObjectIDFromHex(hex); err == nil {
+			return objectID
+		}
+	}
+	return v
+}
+
+// caseInsensitiveRegex helper function for create a regex that match the whole value ignoring the case,
+// the value is escaped so it's compared literally
+func caseInsensitiveRegex(value string) primitive.Regex {
+	return primitive.Regex{
+		Pattern: "^" + regexp.QuoteMeta(value) + "$",
+		Options: "i",
+	}
+}
+
+// earthRadiusInMeters is the radius used by mongo for convert distances to radians
+const earthRadiusInMeters = 6378100
+
+// createMongoGeoCondition helper function for create the $geoWithin expression of a decoded geo value
+func createMongoGeoCondition(value interface{}) bson.M {
+	switch v := value.(type) {
+	case models.GeoDistance:
+		return bson.M{"$geoWithin": bson.M{
+			"$centerSphere": bson.A{bson.A{v.Point.Lon, v.Point.Lat}, v.Meters() / earthRadiusInMeters},
+		}}
+	case models.GeoBoundingBox:
+		// The box is a GeoJSON polygon as the other geo conditions and the sort by distance, they all need GeoJSON
+		// points with a 2dsphere index (the legacy $box only matches legacy coordinate pairs)
+		return bson.M{"$geoWithin": bson.M{"$geometry": geoJSONPolygon([]models.GeoPoint{
+			{Lat: v.BottomRight.Lat, Lon: v.TopLeft.Lon},
+			{Lat: v.BottomRight.Lat, Lon: v.BottomRight.Lon},
+			{Lat: v.TopLeft.Lat, Lon: v.BottomRight.Lon},
+			{Lat: v.TopLeft.Lat, Lon: v.TopLeft.Lon},
+			{Lat: v.BottomRight.Lat, Lon: v.TopLeft.Lon},
+		})}}
+	case models.GeoPolygon:
+		return bson.M{"$geoWithin": bson.M{"$geometry": geoJSONPolygon(v.Closed())}}
+	}
+	return bson.M{}
+}
+
+// geoJSONPoint helper function for create a GeoJSON point, GeoJSON expects the longitude first
+func geoJSONPoint(p models.GeoPoint) bson.M {
+	return bson.M{"type": "Point", "coordinates": bson.A{p.Lon, p.Lat}}
+}
+
+// geoJSONPolygon helper function for create a GeoJSON polygon from a closed ring of points
+func geoJSONPolygon(ring []models.GeoPoint) bson.M {
+	coordinates := bson.A{}
+	for _, p := range ring {
+		coordinates = append(coordinates, bson.A{p.Lon, p.Lat})
+	}
+	return bson.M{"type": "Polygon", "coordinates": bson.A{coordinates}}
+}
@@ -0,0 +1,178 @@
+package searcher_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/date"
+	"github.com/solrac97gr/searcher/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newGeoTranslator(t *testing.T) *searcher.QueryTranslator {
+	t.Helper()
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "places",
+		Fields: map[string]models.FieldMetaData{
+			"location": {Type: models.Geo},
+			"origin":   {Type: models.Geo},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return qt
+}
+
+func TestToMongoRejectsSeveralDistanceSorts(t *testing.T) {
+	qt := newGeoTranslator(t)
+	criteria := models.Criteria{Query: models.Query{Sorts: models.Sorts{
+		{Field: "location", Order: models.ASCOrder, Point: &models.GeoPoint{Lat: 1, Lon: 2}},
+		{Field: "origin", Order: models.ASCOrder, Point: &models.GeoPoint{Lat: 3, Lon: 4}},
+	}}}
+
+	_, err := qt.ToMongo("places", criteria, nil)
+	fieldErrors := models.FieldErrors(err)
+	if len(fieldErrors) != 1 || fieldErrors[0].Path != "query.sorts[1].point" {
+		t.Fatalf("ToMongo() error = %v, want an error for query.sorts[1].point", err)
+	}
+}
+
+func TestToMongoBoundingBoxUsesGeoJSON(t *testing.T) {
+	qt := newGeoTranslator(t)
+	criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+		Field:    "location",
+		Operator: models.GeoBoundingBoxOperator,
+		Value:    models.GeoBoundingBox{TopLeft: models.GeoPoint{Lat: 10, Lon: -20}, BottomRight: models.GeoPoint{Lat: -10, Lon: 20}},
+	}}}}}}
+
+	query, err := qt.ToMongo("places", criteria, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bson.M{"$and": bson.A{bson.M{"location": bson.M{"$geoWithin": bson.M{"$geometry": bson.M{
+		"type": "Polygon",
+		"coordinates": bson.A{bson.A{
+			bson.A{-20.0, -10.0},
+			bson.A{20.0, -10.0},
+			bson.A{20.0, 10.0},
+			bson.A{-20.0, 10.0},
+			bson.A{-20.0, -10.0},
+		}},
+	}}}}}}
+	if got := query["filters"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("filters = %v, want %v", got, want)
+	}
+}
+
+func TestToMongoRejectsBoundingBoxesAcrossTheAntimeridian(t *testing.T) {
+	qt := newGeoTranslator(t)
+	criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+		Field:    "location",
+		Operator: models.GeoBoundingBoxOperator,
+		Value:    models.GeoBoundingBox{TopLeft: models.GeoPoint{Lat: 10, Lon: 170}, BottomRight: models.GeoPoint{Lat: -10, Lon: -170}},
+	}}}}}}
+
+	_, err := qt.ToMongo("places", criteria, nil)
+	fieldErrors := models.FieldErrors(err)
+	if len(fieldErrors) != 1 || fieldErrors[0].Path != "query.filters[0].conditions[0].value" {
+		t.Fatalf("ToMongo() error = %v, want an error for query.filters[0].conditions[0].value", err)
+	}
+}
+
+func TestToMongoDayValuesInLists(t *testing.T) {
+	formatter, err := date.NewFormatter(date.WithLayouts(time.RFC3339, time.DateOnly))
+	if err != nil {
+		t.Fatal(err)
+	}
+	qt, err := searcher.NewQueryTranslator(searcher.WithDateFormatter(formatter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "events",
+		Fields:     map[string]models.FieldMetaData{"created_at": {Type: models.Date}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	day := func(d int) bson.M {
+		start := time.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC)
+		return bson.M{"created_at": bson.M{"$gte": start, "$lt": start.AddDate(0, 0, 1)}}
+	}
+	instant := time.Date(2024, time.January, 5, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		operator models.Operator
+		want     bson.M
+	}{
+		{
+			name:     "in",
+			operator: models.InOperator,
+			want:     bson.M{"$and": bson.A{bson.M{"$or": bson.A{day(1), day(2), bson.M{"created_at": bson.M{"$eq": instant}}}}}},
+		},
+		{
+			name:     "not_in",
+			operator: models.NotInOperator,
+			want:     bson.M{"$and": bson.A{bson.M{"$nor": bson.A{bson.M{"$or": bson.A{day(1), day(2), bson.M{"created_at": bson.M{"$eq": instant}}}}}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria := models.Criteria{Query: models.Query{Filters: models.Filters{{Conditions: models.Conditions{{
+				Field:    "created_at",
+				Operator: tt.operator,
+				Value:    []interface{}{"2024-01-01", "2024-01-02", "2024-01-05T10:30:00Z"},
+			}}}}}}
+
+			query, err := qt.ToMongo("events", criteria, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := query["filters"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filters = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToMongoSuperFiltersOverHiddenFields(t *testing.T) {
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "orders",
+		Fields: map[string]models.FieldMetaData{
+			"created_at": {Type: models.Date, Roles: []string{"admin"}},
+			"owner_id":   {Type: models.ObjectID, Roles: []string{"admin"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := searcher.ContextWithPrincipal(context.Background(), searcher.Principal{Roles: []string{"viewer"}})
+	superFilters := []models.SuperFilter{
+		{Field: "created_at", Operator: models.GreaterAndEqualsThan, Value: "2024-01-05T10:00:00Z"},
+		{Field: "owner_id", Value: "5f1d7f3e9b1e8a3d4c2b1a09"},
+	}
+
+	query, err := qt.ToMongoCtx(ctx, "orders", models.Criteria{}, superFilters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ownerID, _ := primitive.ObjectIDFromHex("5f1d7f3e9b1e8a3d4c2b1a09")
+	want := bson.M{"$and": bson.A{
+		bson.M{"created_at": bson.M{"$gte": time.Date(2024, time.January, 5, 10, 0, 0, 0, time.UTC)}},
+		bson.M{"owner_id": bson.M{"$eq": ownerID}},
+	}}
+	if got := query["filters"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("filters = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+package searcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/i18n"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+// startTranslation notifies the Tracer of the translator of a new translation with a dialect, without Tracer the context is
+// returned as is with a function that does nothing.
+func (ca *QueryTranslator) startTranslation(ctx context.Context, backend string, validMapEntityName string) (context.Context, func(err error)) {
+	if ca.tracer == nil {
+		return ctx, func(error) {}
+	}
+	return ca.tracer.StartTranslation(ctx, backend, validMapEntityName)
+}
+
+// localizeError replaces the messages of the FieldErrors of err with the messages of the catalog of the translator
+// for the locale of the context, err is returned as is without catalog, locale or FieldErrors.
+func (ca *QueryTranslator) localizeError(ctx context.Context, err error) error {
+	locale := i18n.LocaleFromContext(ctx)
+	if err == nil || ca.catalog == nil || locale == "" {
+		return err
+	}
+	var ve models.ValidationErrors
+	var fe *models.FieldError
+	if !errors.As(err, &ve) && !errors.As(err, &fe) {
+		return err
+	}
+
+	localized := ca.catalog.Localize(locale, err)
+	validationErrors := make(models.ValidationErrors, 0, len(localized))
+	for _, fieldError := range localized {
+		validationErrors = append(validationErrors, fieldError)
+	}
+	return fmt.Errorf("%w: %w", sentinels.ErrValidation, validationErrors)
+}
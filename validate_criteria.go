@@ -0,0 +1,139 @@
+package searcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/solrac97gr/searcher/domain/models"
+	"github.com/solrac97gr/searcher/internal/sentinels"
+)
+
+// ValidateCriteria validates the criteria against the valid field set, the pagination and complexity limits of the entity
+// without translating it, all the problems are reported at once as FieldErrors (check models.FieldErrors) so
+// the UI can highlight every invalid filter. The criteria is prepared with the defaults before the validation
+// (check PrepareCriteria) as the translators do, and the values of the Date fields are parsed with the
+// DateFormatter of the translator.
+func (ca *QueryTranslator) ValidateCriteria(validMapEntityName string, criteria models.Criteria) error {
+	return ca.ValidateCriteriaCtx(context.Background(), validMapEntityName, criteria)
+}
+
+// ValidateCriteriaCtx is ValidateCriteria for the principal of the context, the fields that its roles
+// cannot see are reported as unknown fields (check models.FieldMetaData.Roles) and the errors are
+// localized to the locale of the context when the translator has a Catalog (check WithCatalog).
+func (ca *QueryTranslator) ValidateCriteriaCtx(ctx context.Context, validMapEntityName string, criteria models.Criteria) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vf, ok := ca.fieldSets.Lookup(validMapEntityName)
+	if !ok {
+		return fmt.Errorf("%w: %w: %s", sentinels.ErrValidation, ErrFieldSetNotFound, validMapEntityName)
+	}
+	vf = ca.withEntityLimits(vf).ForRoles(rolesFromContext(ctx))
+	return ca.localizeError(ctx, ca.validateCriteria(vf, *ca.prepareCriteria(&criteria, vf.Limits)))
+}
+
+// CriteriaCost returns the cost of the criteria for the entity computed with the cost weights of the translator
+// (check models.Criteria.Cost), the complexity limits can cap it (check WithComplexityLimits).
+func (ca *QueryTranslator) CriteriaCost(validMapEntityName string, criteria models.Criteria) (int, error) {
+	vf, ok := ca.fieldSets.Lookup(validMapEntityName)
+	if !ok {
+		return 0, fmt.Errorf("%w: %w: %s", sentinels.ErrValidation, ErrFieldSetNotFound, validMapEntityName)
+	}
+	return criteria.Cost(vf, ca.costWeights), nil
+}
+
+// withEntityLimits returns the valid fields with the pagination and complexity limits that are not set in the entity
+// taken from the translator.
+func (ca *QueryTranslator) withEntityLimits(vf models.ValidFields) models.ValidFields {
+	vf.Limits = vf.Limits.WithFallback(ca.paginationLimits)
+	vf.Complexity = vf.Complexity.WithFallback(ca.complexityLimits)
+	return vf
+}
+
+// validateCriteria validates a prepared criteria against the valid fields (with the limits already resolved).
+func (ca *QueryTranslator) validateCriteria(vf models.ValidFields, criteria models.Criteria) error {
+	var validationErrors models.ValidationErrors
+	if err := criteria.ValidateAgainst(vf); err != nil && !errors.As(err, &validationErrors) {
+		return err
+	}
+	var complexityErrors models.ValidationErrors
+	if err := criteria.ValidateComplexity(vf.Complexity, vf, ca.costWeights); err != nil && !errors.As(err, &complexityErrors) {
+		return err
+	}
+	validationErrors = append(validationErrors, complexityErrors...)
+
+	// The dates are parsed with the formatter of the translator because the accepted formats depend on its settings
+	for filterIndex, filter := range criteria.Query.Filters {
+		for conditionIndex, condition := range filter.Conditions {
+			fieldMetaData, ok := vf.FilterField(condition.Field.String())
+			if !ok || !fieldMetaData.Type.Equals(models.Date) || condition.Value == nil || condition.Operator.Equals(models.ExistsOperator) {
+				continue
+			}
+			parse := func(path string, v interface{}) {
+				if _, err := ca.parseDate(v); err != nil {
+					validationErrors = append(validationErrors, models.NewFieldError(
+						path, models.TypeMismatchCode, v, "invalid date value for field: "+condition.Field.String(),
+					))
+				}
+			}
+			// The values of the list operators are parsed one by one
+			if condition.Operator.IsList() {
+				list, _ := models.ListValues(condition.Value)
+				for index, v := range list {
+					parse(fmt.Sprintf("%s[%d]", conditionPath(filterIndex, conditionIndex, "value"), index), v)
+				}
+				continue
+			}
+			parse(conditionPath(filterIndex, conditionIndex, "value"), condition.Value)
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return fmt.Errorf("%w: %w", sentinels.ErrValidation, validationErrors)
+	}
+	return nil
+}
+
+// validateSuperFilters validates the operators and values of the super filters reporting all the problems at once,
+// their fields are not checked against the valid fields.
+func validateSuperFilters(superFilters []models.SuperFilter) error {
+	var validationErrors models.ValidationErrors
+	for index, superFilter := range superFilters {
+		if err := superFilter.Validate(); err != nil {
+			validationErrors = append(validationErrors, models.WithPathPrefix(superFilterPath(index, ""), err))
+		}
+	}
+	if len(validationErrors) > 0 {
+		return fmt.Errorf("%w: %w", sentinels.ErrValidation, models.WithPathPrefix("", validationErrors))
+	}
+	return nil
+}
+
+// LintCriteria returns the warnings of the criteria that cannot use the indexes of the entity (check
+// models.Criteria.LintIndexes) without translating it, the criteria is prepared as the translators do.
+func (ca *QueryTranslator) LintCriteria(validMapEntityName string, criteria models.Criteria, superFilters []models.SuperFilter) ([]*models.FieldError, error) {
+	vf, ok := ca.fieldSets.Lookup(validMapEntityName)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w: %s", sentinels.ErrValidation, ErrFieldSetNotFound, validMapEntityName)
+	}
+	vf = ca.withEntityLimits(vf)
+	return ca.prepareCriteria(&criteria, vf.Limits).LintIndexes(vf, superFilters), nil
+}
+
+// lintIndexes returns the index lint warnings of a prepared criteria, none if the lint is disabled.
+func (ca *QueryTranslator) lintIndexes(vf models.ValidFields, criteria models.Criteria, superFilters []models.SuperFilter) []*models.FieldError {
+	if ca.indexLint == IndexLintOff {
+		return nil
+	}
+	return criteria.LintIndexes(vf, superFilters)
+}
+
+// warningErrors converts the lint warnings to ValidationErrors for reject the criteria.
+func warningErrors(warnings []*models.FieldError) models.ValidationErrors {
+	validationErrors := make(models.ValidationErrors, 0, len(warnings))
+	for _, warning := range warnings {
+		validationErrors = append(validationErrors, warning)
+	}
+	return validationErrors
+}
@@ -0,0 +1,56 @@
+package searcher_test
+
+import (
+	"testing"
+
+	"github.com/solrac97gr/searcher"
+	"github.com/solrac97gr/searcher/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTranslatorsAcceptAnyMapValue(t *testing.T) {
+	qt, err := searcher.NewQueryTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AddValidFieldsSet(models.ValidFields{
+		EntityName: "entity",
+		Fields:     map[string]models.FieldMetaData{"meta": {Type: models.String}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		criteria     models.Criteria
+		superFilters []models.SuperFilter
+	}{
+		{
+			name: "condition with map[string]string value",
+			criteria: models.Criteria{Query: models.Query{Filters: models.Filters{{
+				Conditions: models.Conditions{{Field: "meta", Operator: models.EqualsOperator, Value: map[string]string{"a": "b"}}},
+			}}}},
+		},
+		{
+			name:         "super filter with bson.M value",
+			superFilters: []models.SuperFilter{{Field: "meta", Value: bson.M{"a": 1}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := qt.ToMongo("entity", tt.criteria, tt.superFilters); err != nil {
+				t.Errorf("ToMongo() error = %v", err)
+			}
+			if _, err := qt.ToElastic("entity", tt.criteria, tt.superFilters); err != nil {
+				t.Errorf("ToElastic() error = %v", err)
+			}
+		})
+	}
+
+	empty := models.Criteria{Query: models.Query{Filters: models.Filters{{
+		Conditions: models.Conditions{{Field: "meta", Operator: models.EqualsOperator, Value: map[string]string{}}},
+	}}}}
+	if err := qt.ValidateCriteria("entity", empty); err == nil {
+		t.Error("ValidateCriteria() expected an error for an empty map value")
+	}
+}